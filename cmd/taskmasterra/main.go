@@ -4,18 +4,30 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/robertarles/taskmasterra/v2/pkg/board"
 	"github.com/robertarles/taskmasterra/v2/pkg/config"
+	"github.com/robertarles/taskmasterra/v2/pkg/export"
+	"github.com/robertarles/taskmasterra/v2/pkg/history"
+	"github.com/robertarles/taskmasterra/v2/pkg/importer"
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
 	"github.com/robertarles/taskmasterra/v2/pkg/reminder"
+	"github.com/robertarles/taskmasterra/v2/pkg/snapshot"
+	"github.com/robertarles/taskmasterra/v2/pkg/standup"
 	"github.com/robertarles/taskmasterra/v2/pkg/stats"
 	"github.com/robertarles/taskmasterra/v2/pkg/task"
+	"github.com/robertarles/taskmasterra/v2/pkg/taskwarrior"
 	"github.com/robertarles/taskmasterra/v2/pkg/utils"
 	"github.com/robertarles/taskmasterra/v2/pkg/validator"
+	"github.com/robertarles/taskmasterra/v2/pkg/vcs"
 )
 
 // Build information. Populated at build-time.
@@ -49,7 +61,7 @@ func expandPath(path string) (string, error) {
 
 // recordKeep processes a todo file, moving completed tasks to archive and touched tasks to journal.
 // It validates the file first and continues processing even if validation issues are found.
-func recordKeep(filePath string) error {
+func recordKeep(filePath string, gitCommit bool) error {
 	expandedPath, err := expandPath(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to expand file path '%s': %w", filePath, err)
@@ -71,15 +83,99 @@ func recordKeep(filePath string) error {
 		}
 	}
 
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	timestampFormat, err := cfg.JournalTimestampFormat()
+	if err != nil {
+		return fmt.Errorf("invalid timestamp configuration: %w", err)
+	}
+
+	// Keep a copy of the file before it is modified
+	if err := saveSnapshot(expandedPath, cfg); err != nil {
+		return err
+	}
+
 	// Process the tasks
-	if err := task.ProcessTasks(expandedPath); err != nil {
+	opts := task.DefaultProcessOptions()
+	opts.Timestamp = timestampFormat
+	opts.JournalLayout = cfg.JournalLayout
+	summary, err := task.ProcessTasksWithOptions(expandedPath, opts)
+	if err != nil {
 		return fmt.Errorf("failed to process tasks in file '%s': %w", expandedPath, err)
 	}
 
 	fmt.Printf("✅ Successfully processed tasks in %s\n", expandedPath)
+
+	if gitCommit || cfg.GitAutoCommit {
+		if err := commitRecordKeep(expandedPath, summary); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// commitRecordKeep commits the todo, journal and archive files when the todo
+// file is inside a git repository
+func commitRecordKeep(filePath string, summary *task.Summary) error {
+	repo, err := vcs.FindRepo(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to find git repository for '%s': %w", filePath, err)
+	}
+	if repo == nil {
+		fmt.Printf("ℹ️  %s is not in a git repository, skipping commit\n", filePath)
+		return nil
+	}
+
+	jm := journal.NewManager(filePath)
+	paths := []string{filePath, jm.JournalPath, jm.ArchivePath}
+	committed, err := repo.Commit(paths, recordKeepCommitMessage(filepath.Base(filePath), summary))
+	if err != nil {
+		return fmt.Errorf("failed to commit recordkeep changes: %w", err)
+	}
+
+	if committed {
+		fmt.Printf("✅ Committed changes to git repository %s\n", repo.Root)
+	} else {
+		fmt.Println("ℹ️  No changes to commit")
+	}
+	return nil
+}
+
+// recordKeepCommitMessage summarizes the archived and journaled tasks for a git commit
+func recordKeepCommitMessage(fileName string, summary *task.Summary) string {
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("recordkeep %s: %d archived, %d journaled\n",
+		fileName, len(summary.Archived), len(summary.Journaled)))
+
+	sections := []struct {
+		title string
+		lines []string
+	}{
+		{"Archived", summary.Archived},
+		{"Journaled", summary.Journaled},
+	}
+	for _, section := range sections {
+		if len(section.lines) == 0 {
+			continue
+		}
+		message.WriteString(fmt.Sprintf("\n%s:\n", section.title))
+		for _, line := range section.lines {
+			message.WriteString(strings.TrimSpace(line) + "\n")
+		}
+	}
+
+	return message.String()
+}
+
+// newReminderBackend creates the reminder backend selected in the configuration.
+// Tests replace it to use an in-memory backend.
+var newReminderBackend = func(cfg *config.Config) (reminder.Backend, error) {
+	return reminder.NewBackend(cfg.ReminderBackend, cfg.ReminderOptions())
+}
+
 // updateCalendar syncs active tasks from a todo file to macOS Reminders.app.
 // Only tasks marked with !! (active marker) are added to reminders.
 func updateCalendar(filePath string) error {
@@ -104,23 +200,14 @@ func updateCalendar(filePath string) error {
 		}
 	}
 
-	// Load configuration
-	cfg, err := config.LoadConfig("")
+	cfg, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
-	}
-
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid configuration: %w", err)
+		return err
 	}
 
-	// Create reminder service
-	service := reminder.NewService(cfg.ReminderListName)
-
-	// Clear existing reminders
-	if err := service.ClearList(); err != nil {
-		return fmt.Errorf("failed to clear reminder list '%s': %w", cfg.ReminderListName, err)
+	backend, err := newReminderBackend(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create reminder backend: %w", err)
 	}
 
 	// Read file content for processing
@@ -130,40 +217,439 @@ func updateCalendar(filePath string) error {
 	}
 
 	lines := strings.Split(fileContent, "\n")
-	activeCount := 0
-
-	for i, line := range lines {
-		lineNum := i + 1
-		if task.IsActive(line) {
-			activeCount++
-			taskInfo := task.ParseTaskInfo(line)
-			if taskInfo == nil {
-				fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not parse task info on line %d: %s\n", lineNum, line)
-				continue
-			}
 
-			// Create reminder with due date if specified
-			withDueDate := taskInfo.Priority == task.PriorityCritical || taskInfo.Priority == task.PriorityHigh
-			note := fmt.Sprintf("Priority: %s", taskInfo.Priority.String())
-			if taskInfo.Effort > 0 {
-				note += fmt.Sprintf(", Effort: %d", taskInfo.Effort)
-			}
+	listNames := strings.Join(append([]string{cfg.ReminderListName}, cfg.ReminderLists.Lists()...), "', '")
+	existing, err := backend.List()
+	if err != nil {
+		return fmt.Errorf("failed to list reminders in '%s': %w", listNames, err)
+	}
 
-			if err := service.AddReminder(taskInfo.Title, withDueDate, note); err != nil {
-				return fmt.Errorf("failed to add reminder for task on line %d: %w", lineNum, err)
-			}
+	// Reminders completed in the reminder app mark their tasks [X] so that
+	// the next recordkeep journals and archives them
+	completedKeys := make(map[string]bool)
+	for _, r := range existing {
+		if r.Key != "" && r.Completed {
+			completedKeys[r.Key] = true
 		}
 	}
+	if updatedLines, marked := task.CompleteByID(lines, completedKeys); len(marked) > 0 {
+		if err := saveSnapshot(expandedPath, cfg); err != nil {
+			return err
+		}
+		if err := utils.WriteFileContent(expandedPath, strings.Join(updatedLines, "\n")); err != nil {
+			return fmt.Errorf("failed to update original file '%s': %w", expandedPath, err)
+		}
+		lines = updatedLines
+		fmt.Printf("✅ Marked %d tasks completed in reminders as [X] in %s\n", len(marked), expandedPath)
+	}
+
+	policy, err := cfg.DuePolicy()
+	if err != nil {
+		return fmt.Errorf("invalid due date configuration: %w", err)
+	}
+	now := time.Now()
+	var desired []reminder.Reminder
+
+	for _, t := range task.Parse(lines) {
+		if !t.Active || t.Subtask {
+			continue
+		}
+		taskInfo := task.ParseTaskInfo(t.Line)
+
+		// The task ID keeps the reminder matched when the status or priority changes
+		desired = append(desired, reminder.Reminder{
+			Line:     t.LineNumber,
+			List:     cfg.ReminderLists.ListFor(t, cfg.ReminderListName),
+			Key:      t.ID,
+			Title:    taskInfo.Title,
+			Notes:    reminder.TaskNotes(t, expandedPath),
+			Due:      policy.Due(t, now),
+			Priority: taskInfo.Priority.ICalendar(),
+			Flagged:  taskInfo.Priority == task.PriorityCritical,
+		})
+	}
+
+	activeCount := len(desired)
+
+	timestampFormat, err := cfg.JournalTimestampFormat()
+	if err != nil {
+		return fmt.Errorf("invalid timestamp configuration: %w", err)
+	}
+	archived, err := journal.NewManager(expandedPath).ReadArchive(timestampFormat)
+	if err != nil {
+		return fmt.Errorf("failed to read archive for '%s': %w", expandedPath, err)
+	}
+
+	desired, conflicts := resolveReminderConflicts(existing, desired, lines, archived)
+	for _, conflict := range conflicts {
+		fmt.Fprintf(os.Stderr, "⚠️  Conflict: %s\n", conflict)
+	}
+
+	// Tasks are keyed by title, so only the first of several tasks with the same title gets a reminder
+	for _, duplicate := range reminder.Duplicates(desired) {
+		fmt.Fprintf(os.Stderr, "⚠️  Duplicate: '%s' on line %d has the same title as an earlier task and gets no reminder; rename one of them\n", duplicate.Title, duplicate.Line)
+	}
+
+	// Failed changes are reported per task after the changes that went through
+	syncResult, err := reminder.Reconcile(backend, existing, desired)
+	var changeErrs reminder.ChangeErrors
+	if err != nil && !errors.As(err, &changeErrs) {
+		return fmt.Errorf("failed to sync reminder list '%s': %w", listNames, err)
+	}
 
 	if activeCount == 0 {
 		fmt.Printf("ℹ️  No active tasks found in %s\n", expandedPath)
-	} else {
-		fmt.Printf("✅ Successfully added %d active tasks to reminder list '%s'\n", activeCount, cfg.ReminderListName)
+	}
+	fmt.Printf("✅ Synced %d active tasks to reminder list '%s': %d added, %d updated, %d removed, %d unchanged\n",
+		activeCount, listNames, syncResult.Added, syncResult.Updated, syncResult.Removed, syncResult.Unchanged)
+
+	if len(changeErrs) > 0 {
+		for _, changeErr := range changeErrs {
+			fmt.Fprintf(os.Stderr, "❌ %s\n", changeErr)
+		}
+		return fmt.Errorf("%d reminder changes failed in list '%s'", len(changeErrs), listNames)
 	}
 
 	return nil
 }
 
+// resolveReminderConflicts keeps changes made in the reminder app that cannot
+// be applied to the todo file from being overwritten by a sync. Renamed
+// reminders keep their title, and reminders whose task was changed or removed
+// in the todo file are kept instead of removed when they were completed or
+// renamed in the app. It returns the adjusted desired reminders and a
+// description of each conflict.
+func resolveReminderConflicts(existing, desired []reminder.Reminder, lines []string, archived []journal.Entry) ([]reminder.Reminder, []string) {
+	present := make(map[string]bool)
+	for _, line := range lines {
+		if task.IsTask(line) || task.IsSubTask(line) {
+			present[task.ID(line)] = true
+		}
+	}
+	archivedIDs := make(map[string]bool)
+	for _, entry := range archived {
+		archivedIDs[task.ID(entry.Line)] = true
+	}
+	desiredIndex := make(map[string]int)
+	for i, r := range desired {
+		desiredIndex[r.Key] = i
+	}
+
+	var conflicts []string
+	for _, r := range existing {
+		if r.Key == "" {
+			continue
+		}
+		// Reminder titles are keyed like task lines, so a different key means the title was edited in the app
+		renamed := task.ID("- [ ] "+r.Title) != r.Key
+
+		if i, ok := desiredIndex[r.Key]; ok {
+			if renamed && desired[i].Title != r.Title {
+				conflicts = append(conflicts, fmt.Sprintf("'%s' was renamed to '%s' in reminders; update the todo file to match", desired[i].Title, r.Title))
+				desired[i].Title = r.Title
+			}
+			continue
+		}
+
+		switch {
+		case r.Completed && !present[r.Key] && !archivedIDs[r.Key]:
+			conflicts = append(conflicts, fmt.Sprintf("'%s' was completed in reminders but its task was changed or removed in the todo file; update the task or delete the reminder", r.Title))
+		case renamed && !r.Completed:
+			conflicts = append(conflicts, fmt.Sprintf("'%s' was renamed in reminders but its task was changed or is no longer active; update the task or delete the reminder", r.Title))
+		default:
+			continue
+		}
+		desired = append(desired, r)
+		desiredIndex[r.Key] = len(desired) - 1
+	}
+
+	return desired, conflicts
+}
+
+// logOptions holds the command-line filters for the log command.
+type logOptions struct {
+	From     string
+	To       string
+	Tags     string
+	Priority string
+	Status   string
+	Text     string
+	Source   string
+	Format   string
+}
+
+// queryLog searches the journal and archive of a todo file and prints matching entries.
+func queryLog(filePath string, opts logOptions) error {
+	expandedPath, err := expandPath(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to expand file path '%s': %w", filePath, err)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	timestampFormat, err := cfg.JournalTimestampFormat()
+	if err != nil {
+		return fmt.Errorf("invalid timestamp configuration: %w", err)
+	}
+
+	filter, err := buildLogFilter(opts, timestampFormat.Location)
+	if err != nil {
+		return err
+	}
+
+	// RecentJournal and RecentArchive only read back to the start date
+	// when the files use the append layout
+	jm := journal.NewManager(expandedPath)
+	var entries []journal.Entry
+
+	if opts.Source == "" || opts.Source == "all" || opts.Source == journal.SourceJournal {
+		journalEntries, err := jm.RecentJournal(timestampFormat, filter.From)
+		if err != nil {
+			return fmt.Errorf("failed to read journal for '%s': %w", expandedPath, err)
+		}
+		entries = append(entries, journalEntries...)
+	}
+	if opts.Source == "" || opts.Source == "all" || opts.Source == journal.SourceArchive {
+		archiveEntries, err := jm.RecentArchive(timestampFormat, filter.From)
+		if err != nil {
+			return fmt.Errorf("failed to read archive for '%s': %w", expandedPath, err)
+		}
+		entries = append(entries, archiveEntries...)
+	}
+
+	output, err := history.Render(history.Apply(entries, filter), opts.Format, timestampFormat)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(output)
+	return nil
+}
+
+// buildLogFilter converts command-line log options to a history filter.
+// Dates are whole days (YYYY-MM-DD) in the configured timezone; -to is inclusive.
+func buildLogFilter(opts logOptions, loc *time.Location) (history.Filter, error) {
+	var filter history.Filter
+
+	switch opts.Source {
+	case "", "all", journal.SourceJournal, journal.SourceArchive:
+	default:
+		return filter, fmt.Errorf("unknown source '%s' (use journal, archive or all)", opts.Source)
+	}
+
+	if opts.From != "" {
+		from, err := time.ParseInLocation("2006-01-02", opts.From, loc)
+		if err != nil {
+			return filter, fmt.Errorf("invalid -from date '%s', expected YYYY-MM-DD: %w", opts.From, err)
+		}
+		filter.From = from
+	}
+	if opts.To != "" {
+		to, err := time.ParseInLocation("2006-01-02", opts.To, loc)
+		if err != nil {
+			return filter, fmt.Errorf("invalid -to date '%s', expected YYYY-MM-DD: %w", opts.To, err)
+		}
+		filter.Until = to.AddDate(0, 0, 1)
+	}
+
+	if opts.Tags != "" {
+		for _, tag := range strings.Split(opts.Tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+	}
+
+	if opts.Priority != "" {
+		priority, ok := task.ParsePriorityName(opts.Priority)
+		if !ok {
+			return filter, fmt.Errorf("unknown priority '%s' (use A, B, C, D or a priority name)", opts.Priority)
+		}
+		filter.Priority = &priority
+	}
+
+	status, err := history.ParseStatus(opts.Status)
+	if err != nil {
+		return filter, err
+	}
+	filter.Status = status
+	filter.Text = opts.Text
+
+	return filter, nil
+}
+
+// restoreTask moves an archived task, found by ID or title pattern, back into its todo file.
+func restoreTask(filePath string, query string) error {
+	expandedPath, err := expandPath(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to expand file path '%s': %w", filePath, err)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	timestampFormat, err := cfg.JournalTimestampFormat()
+	if err != nil {
+		return fmt.Errorf("invalid timestamp configuration: %w", err)
+	}
+
+	// Keep a copy of the file before it is modified
+	if err := saveSnapshot(expandedPath, cfg); err != nil {
+		return err
+	}
+
+	opts := task.DefaultProcessOptions()
+	opts.Timestamp = timestampFormat
+	entry, err := task.RestoreTask(expandedPath, query, opts)
+	if err != nil {
+		return fmt.Errorf("failed to restore task in '%s': %w", expandedPath, err)
+	}
+
+	section := entry.Section
+	if section == "" {
+		section = "end of file"
+	}
+	fmt.Printf("✅ Restored '%s' to %s (%s)\n", strings.TrimSpace(task.ReopenLine(entry.Line)), expandedPath, section)
+	return nil
+}
+
+// standupReport prints the Yesterday / Today / Blocked report for a todo file
+func standupReport(filePath string, format string) error {
+	expandedPath, err := expandPath(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to expand file path '%s': %w", filePath, err)
+	}
+
+	content, err := utils.ReadFileContent(expandedPath)
+	if err != nil {
+		return fmt.Errorf("failed to read file '%s': %w", expandedPath, err)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	timestampFormat, err := cfg.JournalTimestampFormat()
+	if err != nil {
+		return fmt.Errorf("invalid timestamp configuration: %w", err)
+	}
+
+	now := time.Now().In(timestampFormat.Location)
+	jm := journal.NewManager(expandedPath)
+	entries, err := jm.RecentJournal(timestampFormat, standup.LastWorkingDay(now))
+	if err != nil {
+		return fmt.Errorf("failed to read journal for '%s': %w", expandedPath, err)
+	}
+
+	output, err := standup.Render(standup.Build(entries, strings.Split(content, "\n"), now), format)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(output)
+	return nil
+}
+
+// boardView prints the todo file as a Kanban board. A width of 0 uses the
+// COLUMNS environment variable, or the default width when it is not set.
+func boardView(filePath string, format string, groupBy string, width int) error {
+	expandedPath, err := expandPath(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to expand file path '%s': %w", filePath, err)
+	}
+
+	content, err := utils.ReadFileContent(expandedPath)
+	if err != nil {
+		return fmt.Errorf("failed to read file '%s': %w", expandedPath, err)
+	}
+
+	b, err := board.Build(task.Parse(strings.Split(content, "\n")), groupBy)
+	if err != nil {
+		return err
+	}
+	if width <= 0 {
+		width, _ = strconv.Atoi(os.Getenv("COLUMNS"))
+	}
+	output, err := board.Render(b, format, width)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(output)
+	return nil
+}
+
+// newSnapshotManager creates a snapshot manager using the configured retention.
+func newSnapshotManager(filePath string, cfg *config.Config) *snapshot.Manager {
+	sm := snapshot.NewManager(filePath)
+	sm.KeepCount = cfg.SnapshotKeepCount
+	sm.KeepAge = time.Duration(cfg.SnapshotKeepDays) * 24 * time.Hour
+	return sm
+}
+
+// saveSnapshot saves a copy of a todo file before a command modifies it.
+// Does nothing when snapshots are disabled in the configuration.
+func saveSnapshot(filePath string, cfg *config.Config) error {
+	if cfg.DisableSnapshots {
+		return nil
+	}
+	if _, err := newSnapshotManager(filePath, cfg).Save(); err != nil {
+		return fmt.Errorf("failed to save snapshot of '%s': %w", filePath, err)
+	}
+	return nil
+}
+
+// manageSnapshots lists the snapshots of a todo file or restores one of them.
+func manageSnapshots(action string, filePath string, ref string) error {
+	expandedPath, err := expandPath(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to expand file path '%s': %w", filePath, err)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	sm := newSnapshotManager(expandedPath, cfg)
+
+	switch action {
+	case "list":
+		snapshots, err := sm.List()
+		if err != nil {
+			return fmt.Errorf("failed to list snapshots of '%s': %w", expandedPath, err)
+		}
+		if len(snapshots) == 0 {
+			fmt.Printf("ℹ️  No snapshots found for %s\n", expandedPath)
+			return nil
+		}
+		for i, snap := range snapshots {
+			fmt.Printf("%3d  %s  %8d bytes  %s\n", i+1, snap.Time.Local().Format("2006-01-02 15:04:05"), snap.Size, snap.ID)
+		}
+		return nil
+
+	case "restore":
+		if ref == "" {
+			return fmt.Errorf("a snapshot number or ID is required for snapshots restore")
+		}
+		snap, err := sm.Restore(ref)
+		if err != nil {
+			return fmt.Errorf("failed to restore snapshot of '%s': %w", expandedPath, err)
+		}
+		fmt.Printf("✅ Restored %s from snapshot %s\n", expandedPath, snap.ID)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown snapshots action '%s' (use list or restore)", action)
+	}
+}
+
 // printHelp displays comprehensive help information for the taskmasterra CLI.
 func printHelp() {
 	fmt.Println("Taskmasterra - Markdown-based task management with journaling and Reminders integration")
@@ -173,16 +659,47 @@ func printHelp() {
 	fmt.Println("Commands:")
 	fmt.Println("  recordkeep      Process tasks: archive completed, journal touched tasks")
 	fmt.Println("                  Example: taskmasterra recordkeep -i todo.md")
+	fmt.Println("                  Use -commit to commit the changes when the files are in a git repository")
 	fmt.Println()
 	fmt.Println("  updatereminders Sync active tasks (marked with !!) to macOS Reminders.app")
 	fmt.Println("                  Example: taskmasterra updatereminders -i todo.md")
 	fmt.Println()
+	fmt.Println("  notify          Send Linux desktop notifications for active and overdue tasks")
+	fmt.Println("                  Example: taskmasterra notify -i todo.md -method dbus")
+	fmt.Println()
 	fmt.Println("  stats           Generate comprehensive task statistics report")
 	fmt.Println("                  Example: taskmasterra stats -i todo.md -o report.md")
+	fmt.Println("                  Example: taskmasterra stats -i todo.md -format html -o report.html")
+	fmt.Println()
+	fmt.Println("  review          Weekly review of completed, touched, blocked and stale tasks")
+	fmt.Println("                  Example: taskmasterra review -i todo.md -week -o review.md")
 	fmt.Println()
 	fmt.Println("  validate        Check todo file format and get improvement suggestions")
 	fmt.Println("                  Example: taskmasterra validate -i todo.md")
 	fmt.Println()
+	fmt.Println("  log             Search the journal and archive by date, tag, priority, status or text")
+	fmt.Println("                  Example: taskmasterra log -i todo.md -from 2024-03-04 -to 2024-03-04 -tag work")
+	fmt.Println()
+	fmt.Println("  standup         Print Yesterday / Today / Blocked from the journal and active tasks")
+	fmt.Println("                  Example: taskmasterra standup -i todo.md -format text")
+	fmt.Println()
+	fmt.Println("  board           Show tasks as a Kanban board by status, with WIP counts and effort per column")
+	fmt.Println("                  Example: taskmasterra board -i todo.md -group tag -format markdown")
+	fmt.Println()
+	fmt.Println("  export          Export tasks as iCalendar, todo.txt, Taskwarrior JSON, org-mode, CSV, JSON or YAML")
+	fmt.Println("                  Example: taskmasterra export -i todo.md -format ics -o tasks.ics")
+	fmt.Println()
+	fmt.Println("  import          Merge tasks from todo.txt, Taskwarrior or org-mode into the todo file, journal and archive")
+	fmt.Println("                  Example: taskmasterra import -i todo.md -format todotxt -from todo.txt")
+	fmt.Println()
+	fmt.Println("  restore         Move an archived task back into the todo file and reopen it")
+	fmt.Println("                  Example: taskmasterra restore -i todo.md \"project proposal\"")
+	fmt.Println()
+	fmt.Println("  snapshots       List or restore copies saved before a command modified the todo file")
+	fmt.Println("                  Examples:")
+	fmt.Println("                    taskmasterra snapshots list -i todo.md")
+	fmt.Println("                    taskmasterra snapshots restore -i todo.md 1")
+	fmt.Println()
 	fmt.Println("  config          Manage application configuration")
 	fmt.Println("                  Examples:")
 	fmt.Println("                    taskmasterra config -init    # Initialize default config")
@@ -194,8 +711,9 @@ func printHelp() {
 	fmt.Println("For more information, see: https://github.com/robertarles/taskmasterra")
 }
 
-// generateStats creates a comprehensive statistics report from a todo file.
-func generateStats(filePath string, outputPath string) error {
+// generateStats creates a comprehensive statistics report from a todo file,
+// as markdown or as an HTML page that includes the archive history.
+func generateStats(filePath string, outputPath string, format string) error {
 	expandedPath, err := expandPath(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to expand file path '%s': %w", filePath, err)
@@ -208,7 +726,20 @@ func generateStats(filePath string, outputPath string) error {
 	}
 
 	// Generate report
-	report := stats.GenerateReport(statsData)
+	var report string
+	switch format {
+	case "markdown", "md":
+		report = stats.GenerateReport(statsData)
+	case "html":
+		if _, statsData.Archive, err = readHistory(expandedPath); err != nil {
+			return err
+		}
+		if report, err = stats.GenerateHTMLReport(statsData); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown report format '%s' (use markdown or html)", format)
+	}
 
 	// Save report
 	if err := stats.SaveReport(report, outputPath); err != nil {
@@ -219,6 +750,265 @@ func generateStats(filePath string, outputPath string) error {
 	return nil
 }
 
+// reviewHistory builds a review of the last days from the journal and archive.
+// The report is printed, or saved when outputPath is set.
+func reviewHistory(filePath string, days int, staleDays int, outputPath string) error {
+	expandedPath, err := expandPath(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to expand file path '%s': %w", filePath, err)
+	}
+	if days < 1 {
+		return fmt.Errorf("review period must be at least one day (got %d)", days)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	timestampFormat, err := cfg.JournalTimestampFormat()
+	if err != nil {
+		return fmt.Errorf("invalid timestamp configuration: %w", err)
+	}
+
+	now := time.Now().In(timestampFormat.Location)
+	opts := stats.WeekOptions(now, timestampFormat)
+	opts.From = opts.Until.AddDate(0, 0, -days)
+	opts.StaleDays = staleDays
+
+	review, err := stats.AnalyzeHistory(expandedPath, opts, now)
+	if err != nil {
+		return fmt.Errorf("failed to analyze history of '%s': %w", expandedPath, err)
+	}
+
+	report := stats.GenerateReviewReport(review)
+	if outputPath == "" {
+		fmt.Print(report)
+		return nil
+	}
+
+	if err := stats.SaveReport(report, outputPath); err != nil {
+		return fmt.Errorf("failed to save report to '%s': %w", outputPath, err)
+	}
+
+	fmt.Printf("✅ Review generated and saved to: %s\n", outputPath)
+	return nil
+}
+
+// notifyTasks sends a desktop notification for every active or overdue task.
+// method overrides the configured notification method when set.
+func notifyTasks(filePath string, method string, limit int) error {
+	expandedPath, err := expandPath(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to expand file path '%s': %w", filePath, err)
+	}
+
+	content, err := utils.ReadFileContent(expandedPath)
+	if err != nil {
+		return fmt.Errorf("failed to read file '%s': %w", expandedPath, err)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if method == "" {
+		method = cfg.NotifyMethod
+	}
+	notifier, err := reminder.NewNotifier(method)
+	if err != nil {
+		return err
+	}
+	policy, err := cfg.DuePolicy()
+	if err != nil {
+		return fmt.Errorf("invalid due date configuration: %w", err)
+	}
+
+	alerts := reminder.Alerts(task.Parse(strings.Split(content, "\n")), policy, time.Now())
+	if len(alerts) == 0 {
+		fmt.Println("✅ No active or overdue tasks")
+		return nil
+	}
+
+	sent, err := notifier.NotifyAll(alerts, limit)
+	if err != nil {
+		return fmt.Errorf("sent %d of %d notifications: %w", sent, len(alerts), err)
+	}
+
+	fmt.Printf("✅ Sent %d notifications for %d active or overdue tasks\n", sent, len(alerts))
+	return nil
+}
+
+// exportTasks converts the tasks of a todo file to another format.
+// With includeHistory, formats that can hold it also get the journal and archive.
+// The result is printed, or saved when outputPath is set.
+func exportTasks(filePath string, format string, outputPath string, includeHistory bool) error {
+	expandedPath, err := expandPath(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to expand file path '%s': %w", filePath, err)
+	}
+
+	content, err := utils.ReadFileContent(expandedPath)
+	if err != nil {
+		return fmt.Errorf("failed to read file '%s': %w", expandedPath, err)
+	}
+	tasks := task.Parse(strings.Split(content, "\n"))
+
+	var output string
+	switch format {
+	case "ics":
+		output = export.ICS(tasks, time.Now(), export.DefaultICSOptions())
+	case "json":
+		output, err = export.JSON(export.NewDocument(tasks, expandedPath, time.Now()))
+		if err != nil {
+			return err
+		}
+	case "yaml", "yml":
+		output = export.YAML(export.NewDocument(tasks, expandedPath, time.Now()))
+	case "todotxt", "todo.txt":
+		output = export.TodoTxt(tasks)
+	case "taskwarrior":
+		output, err = export.Taskwarrior(tasks, time.Now())
+		if err != nil {
+			return err
+		}
+	case "org":
+		var opts export.OrgOptions
+		if includeHistory {
+			if opts.Journal, opts.Archive, err = readHistory(expandedPath); err != nil {
+				return err
+			}
+		}
+		output = export.Org(tasks, opts)
+	case "csv":
+		var opts export.CSVOptions
+		if includeHistory {
+			if opts.Journal, opts.Archive, err = readHistory(expandedPath); err != nil {
+				return err
+			}
+		}
+		if output, err = export.CSV(tasks, opts); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown export format '%s' (use ics, json, yaml, csv, todotxt, taskwarrior or org)", format)
+	}
+
+	if outputPath == "" {
+		fmt.Print(output)
+		return nil
+	}
+
+	outputDir := filepath.Dir(outputPath)
+	if err := utils.EnsureDirectoryExists(outputDir); err != nil {
+		return fmt.Errorf("failed to create output directory '%s': %w", outputDir, err)
+	}
+	if err := utils.WriteFileContent(outputPath, output); err != nil {
+		return fmt.Errorf("failed to write export '%s': %w", outputPath, err)
+	}
+
+	fmt.Printf("✅ Exported %s to: %s\n", format, outputPath)
+	return nil
+}
+
+// loadConfig loads and validates the user's configuration
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// readHistory reads the journal and archive entries of a todo file
+func readHistory(filePath string) ([]journal.Entry, []journal.Entry, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timestampFormat, err := cfg.JournalTimestampFormat()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid timestamp configuration: %w", err)
+	}
+
+	jm := journal.NewManager(filePath)
+	journalEntries, err := jm.ReadJournal(timestampFormat)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read journal for '%s': %w", filePath, err)
+	}
+	archiveEntries, err := jm.ReadArchive(timestampFormat)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read archive for '%s': %w", filePath, err)
+	}
+	return journalEntries, archiveEntries, nil
+}
+
+// importTasks merges the tasks of a file written by another tool into a todo
+// file and its archive.
+func importTasks(filePath string, format string, sourcePath string) error {
+	expandedPath, err := expandPath(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to expand file path '%s': %w", filePath, err)
+	}
+	expandedSource, err := expandPath(sourcePath)
+	if err != nil {
+		return fmt.Errorf("failed to expand file path '%s': %w", sourcePath, err)
+	}
+
+	source, err := utils.ReadFileContent(expandedSource)
+	if err != nil {
+		return fmt.Errorf("failed to read file '%s': %w", expandedSource, err)
+	}
+
+	var items []importer.Item
+	switch format {
+	case "todotxt", "todo.txt":
+		items = importer.TodoTxt(source, time.Local)
+	case "taskwarrior":
+		tasks, err := taskwarrior.Parse([]byte(source))
+		if err != nil {
+			return fmt.Errorf("failed to read Taskwarrior export '%s': %w", expandedSource, err)
+		}
+		items = importer.Taskwarrior(tasks, time.Local)
+	case "org":
+		items = importer.Org(source, time.Local)
+	default:
+		return fmt.Errorf("unknown import format '%s' (use todotxt, taskwarrior or org)", format)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	timestampFormat, err := cfg.JournalTimestampFormat()
+	if err != nil {
+		return fmt.Errorf("invalid timestamp configuration: %w", err)
+	}
+
+	// Keep a copy of the file before it is modified
+	if err := saveSnapshot(expandedPath, cfg); err != nil {
+		return err
+	}
+
+	opts := task.DefaultProcessOptions()
+	opts.Timestamp = timestampFormat
+	opts.JournalLayout = cfg.JournalLayout
+	result, err := importer.Import(expandedPath, items, opts)
+	if err != nil {
+		return fmt.Errorf("failed to import tasks into '%s': %w", expandedPath, err)
+	}
+
+	fmt.Printf("✅ Imported %s into %s: %d added, %d marked completed, %d archived, %d journaled, %d already present\n",
+		expandedSource, expandedPath, result.Added, result.Completed, result.Archived, result.Journaled, result.Skipped)
+	return nil
+}
+
 // validateFile validates a todo file and displays any issues found.
 func validateFile(filePath string) error {
 	expandedPath, err := expandPath(filePath)
@@ -346,7 +1136,7 @@ func min(a, b, c int) int {
 }
 
 func main() {
-	validCommands := []string{"updatereminders", "updatecal", "recordkeep", "stats", "validate", "config", "version", "help"}
+	validCommands := []string{"updatereminders", "updatecal", "recordkeep", "stats", "review", "validate", "log", "standup", "board", "export", "import", "notify", "restore", "snapshots", "config", "version", "help"}
 
 	if len(os.Args) < 2 {
 		printHelp()
@@ -382,8 +1172,9 @@ func main() {
 	case "recordkeep":
 		recordKeepCmd := flag.NewFlagSet("recordkeep", flag.ExitOnError)
 		inputFilePath := recordKeepCmd.String("i", "", "Path to the markdown input file")
+		gitCommit := recordKeepCmd.Bool("commit", false, "Commit the todo, journal and archive files if they are in a git repository")
 		recordKeepCmd.Usage = func() {
-			fmt.Println("\nUsage: taskmasterra recordkeep -i <inputfile>")
+			fmt.Println("\nUsage: taskmasterra recordkeep -i <inputfile> [-commit]")
 			fmt.Println("Process tasks: archive completed, journal touched tasks")
 			recordKeepCmd.PrintDefaults()
 		}
@@ -397,7 +1188,7 @@ func main() {
 			recordKeepCmd.Usage()
 			return
 		}
-		if err := recordKeep(*inputFilePath); err != nil {
+		if err := recordKeep(*inputFilePath, *gitCommit); err != nil {
 			fmt.Printf("Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -406,9 +1197,11 @@ func main() {
 		statsCmd := flag.NewFlagSet("stats", flag.ExitOnError)
 		inputFilePath := statsCmd.String("i", "", "Path to the markdown input file")
 		outputFilePath := statsCmd.String("o", "", "Path to the output statistics report file")
+		format := statsCmd.String("format", "markdown", "Report format: markdown or html")
 		statsCmd.Usage = func() {
-			fmt.Println("\nUsage: taskmasterra stats -i <inputfile> -o <outputfile>")
+			fmt.Println("\nUsage: taskmasterra stats -i <inputfile> -o <outputfile> [-format markdown|html]")
 			fmt.Println("Generate comprehensive task statistics report")
+			fmt.Println("html: a self-contained page with status and priority charts, a filterable task table and the archive history")
 			statsCmd.PrintDefaults()
 		}
 		if err := statsCmd.Parse(os.Args[2:]); err != nil {
@@ -421,7 +1214,37 @@ func main() {
 			statsCmd.Usage()
 			return
 		}
-		if err := generateStats(*inputFilePath, *outputFilePath); err != nil {
+		if err := generateStats(*inputFilePath, *outputFilePath, *format); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "review":
+		reviewCmd := flag.NewFlagSet("review", flag.ExitOnError)
+		inputFilePath := reviewCmd.String("i", "", "Path to the markdown input file")
+		outputFilePath := reviewCmd.String("o", "", "Path to the output review file (default: print)")
+		week := reviewCmd.Bool("week", false, "Review the last 7 days (the default period)")
+		days := reviewCmd.Int("days", 7, "Number of days to review, ending today")
+		staleDays := reviewCmd.Int("stale-days", stats.DefaultStaleDays, "Days without activity after which an open task is stale")
+		reviewCmd.Usage = func() {
+			fmt.Println("\nUsage: taskmasterra review -i <inputfile> -week [-o <outputfile>]")
+			fmt.Println("Build a review of completed, touched, blocked and stale tasks from the journal and archive")
+			reviewCmd.PrintDefaults()
+		}
+		if err := reviewCmd.Parse(os.Args[2:]); err != nil {
+			fmt.Printf("Error parsing flags: %v\n", err)
+			reviewCmd.Usage()
+			os.Exit(1)
+		}
+		if *inputFilePath == "" {
+			fmt.Println("Error: Input file path is required for review command. Use -i to specify the path.")
+			reviewCmd.Usage()
+			return
+		}
+		if *week {
+			*days = 7
+		}
+		if err := reviewHistory(*inputFilePath, *days, *staleDays, *outputFilePath); err != nil {
 			fmt.Printf("Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -449,6 +1272,230 @@ func main() {
 			os.Exit(1)
 		}
 
+	case "log":
+		logCmd := flag.NewFlagSet("log", flag.ExitOnError)
+		inputFilePath := logCmd.String("i", "", "Path to the markdown input file")
+		var opts logOptions
+		logCmd.StringVar(&opts.From, "from", "", "Only entries on or after this date (YYYY-MM-DD)")
+		logCmd.StringVar(&opts.To, "to", "", "Only entries on or before this date (YYYY-MM-DD)")
+		logCmd.StringVar(&opts.Tags, "tag", "", "Only entries with these tags (comma-separated)")
+		logCmd.StringVar(&opts.Priority, "priority", "", "Only entries with this priority (A, B, C, D)")
+		logCmd.StringVar(&opts.Status, "status", "", "Only entries with this status (open, worked, blocked, done)")
+		logCmd.StringVar(&opts.Text, "text", "", "Only entries containing this text")
+		logCmd.StringVar(&opts.Source, "source", "all", "Which files to search: journal, archive or all")
+		logCmd.StringVar(&opts.Format, "format", "text", "Output format: text, markdown or json")
+		logCmd.Usage = func() {
+			fmt.Println("\nUsage: taskmasterra log -i <inputfile> [filters]")
+			fmt.Println("Search the journal and archive by date, tag, priority, status or text")
+			logCmd.PrintDefaults()
+		}
+		if err := logCmd.Parse(os.Args[2:]); err != nil {
+			fmt.Printf("Error parsing flags: %v\n", err)
+			logCmd.Usage()
+			os.Exit(1)
+		}
+		if *inputFilePath == "" {
+			fmt.Println("Error: Input file path is required for log command. Use -i to specify the path.")
+			logCmd.Usage()
+			return
+		}
+		if err := queryLog(*inputFilePath, opts); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "standup":
+		standupCmd := flag.NewFlagSet("standup", flag.ExitOnError)
+		inputFilePath := standupCmd.String("i", "", "Path to the markdown input file")
+		format := standupCmd.String("format", "markdown", "Output format: markdown or text")
+		standupCmd.Usage = func() {
+			fmt.Println("\nUsage: taskmasterra standup -i <inputfile> [-format markdown|text]")
+			fmt.Println("Print a Yesterday / Today / Blocked report from the journal and active tasks")
+			standupCmd.PrintDefaults()
+		}
+		if err := standupCmd.Parse(os.Args[2:]); err != nil {
+			fmt.Printf("Error parsing flags: %v\n", err)
+			standupCmd.Usage()
+			os.Exit(1)
+		}
+		if *inputFilePath == "" {
+			fmt.Println("Error: Input file path is required for standup command. Use -i to specify the path.")
+			standupCmd.Usage()
+			return
+		}
+		if err := standupReport(*inputFilePath, *format); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "board":
+		boardCmd := flag.NewFlagSet("board", flag.ExitOnError)
+		inputFilePath := boardCmd.String("i", "", "Path to the markdown input file")
+		format := boardCmd.String("format", "text", "Output format: text or markdown")
+		groupBy := boardCmd.String("group", "section", "Group tasks by section, tag or none")
+		width := boardCmd.Int("width", 0, "Terminal width for the text view (default: $COLUMNS, or 120)")
+		boardCmd.Usage = func() {
+			fmt.Println("\nUsage: taskmasterra board -i <inputfile> [-format text|markdown] [-group section|tag|none] [-width n]")
+			fmt.Println("Show tasks as a Kanban board with Open / Worked / Blocked / Done columns, their WIP counts and effort totals")
+			boardCmd.PrintDefaults()
+		}
+		if err := boardCmd.Parse(os.Args[2:]); err != nil {
+			fmt.Printf("Error parsing flags: %v\n", err)
+			boardCmd.Usage()
+			os.Exit(1)
+		}
+		if *inputFilePath == "" {
+			fmt.Println("Error: Input file path is required for board command. Use -i to specify the path.")
+			boardCmd.Usage()
+			return
+		}
+		if err := boardView(*inputFilePath, *format, *groupBy, *width); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "notify":
+		notifyCmd := flag.NewFlagSet("notify", flag.ExitOnError)
+		inputFilePath := notifyCmd.String("i", "", "Path to the markdown input file")
+		method := notifyCmd.String("method", "", "Notification method: notify-send or dbus (default: notify_method from the config)")
+		limit := notifyCmd.Int("limit", reminder.DefaultAlertLimit, "Number of notifications sent before the rest are summarized, 0 for no limit")
+		notifyCmd.Usage = func() {
+			fmt.Println("\nUsage: taskmasterra notify -i <inputfile> [-method notify-send|dbus] [-limit n]")
+			fmt.Println("Send a desktop notification for every active (!!) or overdue task")
+			notifyCmd.PrintDefaults()
+		}
+		if err := notifyCmd.Parse(os.Args[2:]); err != nil {
+			fmt.Printf("Error parsing flags: %v\n", err)
+			notifyCmd.Usage()
+			os.Exit(1)
+		}
+		if *inputFilePath == "" {
+			fmt.Println("Error: Input file path is required for notify command. Use -i to specify the path.")
+			notifyCmd.Usage()
+			return
+		}
+		if err := notifyTasks(*inputFilePath, *method, *limit); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "export":
+		exportCmd := flag.NewFlagSet("export", flag.ExitOnError)
+		inputFilePath := exportCmd.String("i", "", "Path to the markdown input file")
+		outputFilePath := exportCmd.String("o", "", "Path to the output file (default: print)")
+		format := exportCmd.String("format", "ics", "Output format: ics, json, yaml, csv, todotxt, taskwarrior or org")
+		includeHistory := exportCmd.Bool("history", false, "Include the journal and archive (org, csv)")
+		exportCmd.Usage = func() {
+			fmt.Println("\nUsage: taskmasterra export -i <inputfile> -format ics|json|yaml|csv|todotxt|taskwarrior|org [-history] [-o <outputfile>]")
+			fmt.Println("ics: export tasks with dates, repeaters or the !! marker as VTODO and VEVENT entries")
+			fmt.Printf("json, yaml: export every task with its status, priority, effort, tags and details (schema version %d)\n", export.SchemaVersion)
+			fmt.Println("csv: export one row per task for spreadsheets; -history adds a row per journal and archive entry")
+			fmt.Println("todotxt: export every task as a todo.txt line with its priority, +section, @tags and due: date")
+			fmt.Println("taskwarrior: export every task as JSON for 'task import'")
+			fmt.Println("org: export every task as an org-mode headline; -history adds journal LOGBOOKs and archived tasks")
+			exportCmd.PrintDefaults()
+		}
+		if err := exportCmd.Parse(os.Args[2:]); err != nil {
+			fmt.Printf("Error parsing flags: %v\n", err)
+			exportCmd.Usage()
+			os.Exit(1)
+		}
+		if *inputFilePath == "" {
+			fmt.Println("Error: Input file path is required for export command. Use -i to specify the path.")
+			exportCmd.Usage()
+			return
+		}
+		if err := exportTasks(*inputFilePath, *format, *outputFilePath, *includeHistory); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "import":
+		importCmd := flag.NewFlagSet("import", flag.ExitOnError)
+		inputFilePath := importCmd.String("i", "", "Path to the markdown todo file to import into")
+		sourceFilePath := importCmd.String("from", "", "Path to the file to import")
+		format := importCmd.String("format", "todotxt", "Format of the imported file: todotxt, taskwarrior ('task export' output) or org")
+		importCmd.Usage = func() {
+			fmt.Println("\nUsage: taskmasterra import -i <inputfile> -format todotxt|taskwarrior|org -from <file>")
+			fmt.Println("Add open tasks missing from the todo file, mark tasks completed in the other tool [X],")
+			fmt.Println("archive completed tasks that are not in the todo file and journal recorded work times.")
+			fmt.Println("Importing again changes nothing.")
+			importCmd.PrintDefaults()
+		}
+		if err := importCmd.Parse(os.Args[2:]); err != nil {
+			fmt.Printf("Error parsing flags: %v\n", err)
+			importCmd.Usage()
+			os.Exit(1)
+		}
+		if *inputFilePath == "" || *sourceFilePath == "" {
+			fmt.Println("Error: Input file path and source file are required for import command. Use -i and -from to specify them.")
+			importCmd.Usage()
+			return
+		}
+		if err := importTasks(*inputFilePath, *format, *sourceFilePath); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "restore":
+		restoreCmd := flag.NewFlagSet("restore", flag.ExitOnError)
+		inputFilePath := restoreCmd.String("i", "", "Path to the markdown input file")
+		restoreCmd.Usage = func() {
+			fmt.Println("\nUsage: taskmasterra restore -i <inputfile> <id|pattern>")
+			fmt.Println("Move an archived task back into the todo file and reopen it")
+			fmt.Println("The task is found by the ID shown by 'taskmasterra log' or by part of its title")
+			restoreCmd.PrintDefaults()
+		}
+		if err := restoreCmd.Parse(os.Args[2:]); err != nil {
+			fmt.Printf("Error parsing flags: %v\n", err)
+			restoreCmd.Usage()
+			os.Exit(1)
+		}
+		if *inputFilePath == "" {
+			fmt.Println("Error: Input file path is required for restore command. Use -i to specify the path.")
+			restoreCmd.Usage()
+			return
+		}
+		if restoreCmd.NArg() == 0 {
+			fmt.Println("Error: A task ID or title pattern is required for restore command.")
+			restoreCmd.Usage()
+			return
+		}
+		if err := restoreTask(*inputFilePath, strings.Join(restoreCmd.Args(), " ")); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "snapshots":
+		snapshotsCmd := flag.NewFlagSet("snapshots", flag.ExitOnError)
+		inputFilePath := snapshotsCmd.String("i", "", "Path to the markdown input file")
+		snapshotsCmd.Usage = func() {
+			fmt.Println("\nUsage: taskmasterra snapshots list -i <inputfile>")
+			fmt.Println("       taskmasterra snapshots restore -i <inputfile> <number|id>")
+			fmt.Println("List or restore copies saved before a command modified the todo file")
+			snapshotsCmd.PrintDefaults()
+		}
+		if len(os.Args) < 3 {
+			fmt.Println("Error: An action (list or restore) is required for snapshots command.")
+			snapshotsCmd.Usage()
+			return
+		}
+		action := os.Args[2]
+		if err := snapshotsCmd.Parse(os.Args[3:]); err != nil {
+			fmt.Printf("Error parsing flags: %v\n", err)
+			snapshotsCmd.Usage()
+			os.Exit(1)
+		}
+		if *inputFilePath == "" {
+			fmt.Println("Error: Input file path is required for snapshots command. Use -i to specify the path.")
+			snapshotsCmd.Usage()
+			return
+		}
+		if err := manageSnapshots(action, *inputFilePath, snapshotsCmd.Arg(0)); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
 	case "config":
 		configCmd := flag.NewFlagSet("config", flag.ExitOnError)
 		configFilePath := configCmd.String("c", "", "Path to the configuration file")
@@ -9,7 +9,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/robertarles/taskmasterra/v2/pkg/config"
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
 	"github.com/robertarles/taskmasterra/v2/pkg/reminder"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
 )
 
 // Save the original exec.Command
@@ -60,6 +63,10 @@ func TestHelperProcess(t *testing.T) {
 				os.Exit(1)
 			}
 		}
+		// A batch of reminder changes reports one result per change
+		for i := 0; i < strings.Count(script, "on error errMsg"); i++ {
+			fmt.Print("ok\x1f\x1e")
+		}
 		os.Exit(0)
 	default:
 		os.Exit(1)
@@ -187,7 +194,7 @@ func TestRecordKeep(t *testing.T) {
 				t.Fatalf("Failed to write todo file: %v", err)
 			}
 
-			err := recordKeep(todoPath)
+			err := recordKeep(todoPath, false)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("recordKeep() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -451,4 +458,362 @@ func TestMain_Commands(t *testing.T) {
 			}
 		})
 	}
-} 
\ No newline at end of file
+} 
+func TestQueryLog(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "log-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	todoPath := filepath.Join(tmpDir, "todo.md")
+	journalContent := `[2024-03-05 10:00:00 UTC] - [W] B2 Review pull request #work
+  - left comments
+[2024-03-04 10:00:00 UTC] - [B] C3 Fix the fence #home
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "todo.xjournal.md"), []byte(journalContent), 0644); err != nil {
+		t.Fatalf("Failed to write journal file: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		opts        logOptions
+		wantContain string
+		wantMissing string
+		wantErr     bool
+	}{
+		{
+			name:        "Filter by tag",
+			opts:        logOptions{Tags: "work", Format: "text"},
+			wantContain: "Review pull request",
+			wantMissing: "Fix the fence",
+		},
+		{
+			name:        "Filter by single day",
+			opts:        logOptions{From: "2024-03-04", To: "2024-03-04", Format: "markdown"},
+			wantContain: "Fix the fence",
+			wantMissing: "Review pull request",
+		},
+		{
+			name:    "Invalid date",
+			opts:    logOptions{From: "last tuesday"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid source",
+			opts:    logOptions{Source: "calendar"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			err := queryLog(todoPath, tt.opts)
+
+			w.Close()
+			os.Stdout = oldStdout
+
+			var buf bytes.Buffer
+			if _, err := buf.ReadFrom(r); err != nil {
+				t.Fatalf("Failed to read captured output: %v", err)
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("queryLog() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			output := buf.String()
+			if tt.wantContain != "" && !strings.Contains(output, tt.wantContain) {
+				t.Errorf("Output does not contain %q:\n%s", tt.wantContain, output)
+			}
+			if tt.wantMissing != "" && strings.Contains(output, tt.wantMissing) {
+				t.Errorf("Output should not contain %q:\n%s", tt.wantMissing, output)
+			}
+		})
+	}
+}
+
+func TestRestoreTask(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "restore-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	todoPath := filepath.Join(tmpDir, "todo.md")
+	if err := os.WriteFile(todoPath, []byte("# Tasks\n\n## Work\n\n- [ ] A1 Current\n"), 0644); err != nil {
+		t.Fatalf("Failed to write todo file: %v", err)
+	}
+	archive := "[2024-03-05 10:00:00 UTC] - [x] B2 Archived too early <!-- section: Work -->\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "todo.xarchive.md"), []byte(archive), 0644); err != nil {
+		t.Fatalf("Failed to write archive file: %v", err)
+	}
+
+	if err := restoreTask(todoPath, "too early"); err != nil {
+		t.Fatalf("restoreTask() failed: %v", err)
+	}
+	content, err := os.ReadFile(todoPath)
+	if err != nil {
+		t.Fatalf("Failed to read todo file: %v", err)
+	}
+	if !strings.Contains(string(content), "- [ ] A1 Current\n- [ ] B2 Archived too early") {
+		t.Errorf("Task not restored into its section:\n%s", content)
+	}
+
+	if err := restoreTask(todoPath, "too early"); err == nil {
+		t.Error("Expected error restoring a task that is no longer archived")
+	}
+}
+
+func TestManageSnapshots(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "snapshots-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	todoPath := filepath.Join(tmpDir, "todo.md")
+	original := "# Tasks\n- [x] Done task\n- [ ] Open task\n"
+	if err := os.WriteFile(todoPath, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to write todo file: %v", err)
+	}
+
+	// recordkeep snapshots the file before archiving the completed task
+	if err := recordKeep(todoPath, false); err != nil {
+		t.Fatalf("recordKeep() failed: %v", err)
+	}
+	if err := manageSnapshots("list", todoPath, ""); err != nil {
+		t.Fatalf("manageSnapshots(list) failed: %v", err)
+	}
+
+	if err := manageSnapshots("restore", todoPath, "1"); err != nil {
+		t.Fatalf("manageSnapshots(restore) failed: %v", err)
+	}
+	content, err := os.ReadFile(todoPath)
+	if err != nil {
+		t.Fatalf("Failed to read todo file: %v", err)
+	}
+	if string(content) != original {
+		t.Errorf("Restored content = %q, want %q", content, original)
+	}
+
+	if err := manageSnapshots("restore", todoPath, ""); err == nil {
+		t.Error("Expected error when restoring without a snapshot reference")
+	}
+	if err := manageSnapshots("delete", todoPath, ""); err == nil {
+		t.Error("Expected error for unknown action")
+	}
+}
+
+func TestRecordKeep_GitCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "recordkeep-git-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	git := func(args ...string) string {
+		out, err := exec.Command("git", append([]string{"-C", tmpDir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+		return string(out)
+	}
+	git("init", "-q")
+	git("config", "user.name", "Test")
+	git("config", "user.email", "test@example.com")
+	git("config", "commit.gpgsign", "false")
+
+	todoPath := filepath.Join(tmpDir, "todo.md")
+	content := "# Tasks\n- [x] Ship release\n- [W] Write docs\n- [ ] Later\n"
+	if err := os.WriteFile(todoPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write todo file: %v", err)
+	}
+
+	if err := recordKeep(todoPath, true); err != nil {
+		t.Fatalf("recordKeep() failed: %v", err)
+	}
+
+	log := git("log", "-1", "--format=%B", "--name-only")
+	for _, want := range []string{"recordkeep todo.md: 1 archived, 1 journaled", "- [x] Ship release", "- [W] Write docs", "todo.md", "todo.xjournal.md", "todo.xarchive.md"} {
+		if !strings.Contains(log, want) {
+			t.Errorf("commit missing %q:\n%s", want, log)
+		}
+	}
+	if strings.Contains(log, "snapshots") {
+		t.Errorf("snapshots should not be committed:\n%s", log)
+	}
+}
+
+func TestRecordKeepCommitMessage(t *testing.T) {
+	summary := &task.Summary{Archived: []string{"- [x] Done"}}
+	got := recordKeepCommitMessage("todo.md", summary)
+	want := "recordkeep todo.md: 1 archived, 0 journaled\n\nArchived:\n- [x] Done\n"
+	if got != want {
+		t.Errorf("recordKeepCommitMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestStandupReport(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "standup-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	todoPath := filepath.Join(tmpDir, "todo.md")
+	if err := os.WriteFile(todoPath, []byte("- [ ] !! Write docs\n- [b] Deploy\n"), 0644); err != nil {
+		t.Fatalf("Failed to write todo file: %v", err)
+	}
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err = standupReport(todoPath, "text")
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+
+	if err != nil {
+		t.Fatalf("standupReport() failed: %v", err)
+	}
+	for _, want := range []string{"Today:\n  * Write docs", "Blocked:\n  * Deploy"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	if err := standupReport(todoPath, "html"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestUpdateCalendar_MemoryBackend(t *testing.T) {
+	backend := reminder.NewMemoryBackend()
+	backend.Add(reminder.Reminder{Title: "Added by hand"})
+	backend.Add(reminder.Reminder{Key: task.ID("- [ ] !! Finished elsewhere"), Title: "!! Finished elsewhere"})
+	backend.Add(reminder.Reminder{Key: task.ID("- [ ] !! Later C1"), Title: "!! Later C1", Notes: "user notes", Completed: true})
+
+	originalBackend := newReminderBackend
+	defer func() { newReminderBackend = originalBackend }()
+	newReminderBackend = func(cfg *config.Config) (reminder.Backend, error) { return backend, nil }
+
+	tmpDir, err := os.MkdirTemp("", "updatecal-memory-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	todoPath := filepath.Join(tmpDir, "todo.md")
+	content := "- [ ] !! Urgent A2\n- [ ] !! Later C1\n- [ ] Not active\n"
+	if err := os.WriteFile(todoPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write todo file: %v", err)
+	}
+
+	if err := updateCalendar(todoPath); err != nil {
+		t.Fatalf("updateCalendar() failed: %v", err)
+	}
+
+	reminders, _ := backend.List()
+	if len(reminders) != 3 {
+		t.Fatalf("got %d reminders, want 3: %+v", len(reminders), reminders)
+	}
+	if reminders[0].Title != "Added by hand" {
+		t.Errorf("reminders[0] = %+v, reminders without a key should be kept", reminders[0])
+	}
+	if reminders[1].Title != "!! Later C1" || reminders[1].Notes != "user notes" || !reminders[1].Completed {
+		t.Errorf("reminders[1] = %+v, want existing reminder kept with its notes and completion", reminders[1])
+	}
+	if reminders[2].Title != "!! Urgent A2" || reminders[2].Due.IsZero() || !reminders[2].Flagged {
+		t.Errorf("reminders[2] = %+v, want new flagged high priority reminder with due date", reminders[2])
+	}
+	if want := "Priority: Critical, Effort: 2\n\nSource: " + todoPath + ":1"; reminders[2].Notes != want {
+		t.Errorf("reminders[2].Notes = %q, want %q", reminders[2].Notes, want)
+	}
+
+	// A second sync with no changes leaves everything alone
+	if err := updateCalendar(todoPath); err != nil {
+		t.Fatalf("updateCalendar() failed: %v", err)
+	}
+	if again, _ := backend.List(); len(again) != 3 || again[2].ID != reminders[2].ID {
+		t.Errorf("second sync changed reminders: %+v", again)
+	}
+}
+
+func TestUpdateCalendar_TwoWay(t *testing.T) {
+	backend := reminder.NewMemoryBackend()
+	backend.Add(reminder.Reminder{Key: task.ID("- [ ] !! Pay rent"), Title: "!! Pay rent", Completed: true})
+	backend.Add(reminder.Reminder{Key: task.ID("- [ ] !! Call mom"), Title: "!! Call mom tonight"})
+	backend.Add(reminder.Reminder{Key: task.ID("- [ ] !! Renamed in markdown"), Title: "!! Renamed in markdown", Completed: true})
+	backend.Add(reminder.Reminder{Key: task.ID("- [ ] !! Archived already"), Title: "!! Archived already", Completed: true})
+
+	originalBackend := newReminderBackend
+	defer func() { newReminderBackend = originalBackend }()
+	newReminderBackend = func(cfg *config.Config) (reminder.Backend, error) { return backend, nil }
+
+	tmpDir, err := os.MkdirTemp("", "updatecal-twoway-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	todoPath := filepath.Join(tmpDir, "todo.md")
+	content := "- [ ] !! Pay rent\n- [ ] !! Call mom\n- [ ] !! Renamed in the markdown file\n"
+	if err := os.WriteFile(todoPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write todo file: %v", err)
+	}
+	archive := journal.FormatTimestamp() + " - [x] !! Archived already\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "todo.xarchive.md"), []byte(archive), 0644); err != nil {
+		t.Fatalf("Failed to write archive file: %v", err)
+	}
+
+	oldStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	err = updateCalendar(todoPath)
+
+	w.Close()
+	os.Stderr = oldStderr
+	var stderr bytes.Buffer
+	stderr.ReadFrom(r)
+
+	if err != nil {
+		t.Fatalf("updateCalendar() failed: %v", err)
+	}
+
+	updated, _ := os.ReadFile(todoPath)
+	if !strings.Contains(string(updated), "- [X] !! Pay rent") {
+		t.Errorf("completed reminder should mark its task [X]:\n%s", updated)
+	}
+
+	for _, want := range []string{"renamed to '!! Call mom tonight'", "'!! Renamed in markdown' was completed in reminders"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("stderr missing conflict %q:\n%s", want, stderr.String())
+		}
+	}
+
+	titles := make(map[string]bool)
+	reminders, _ := backend.List()
+	for _, r := range reminders {
+		titles[r.Title] = true
+	}
+	for _, want := range []string{"!! Pay rent", "!! Call mom tonight", "!! Renamed in markdown", "!! Renamed in the markdown file"} {
+		if !titles[want] {
+			t.Errorf("missing reminder %q in %+v", want, reminders)
+		}
+	}
+	if titles["!! Call mom"] || titles["!! Archived already"] {
+		t.Errorf("unexpected reminders %+v", reminders)
+	}
+}
@@ -0,0 +1,345 @@
+// Package board lays out the tasks of a todo file as a Kanban board with Open,
+// Worked, Blocked and Done columns, for the terminal or as a markdown table.
+package board
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+// Output formats supported by Render
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+)
+
+// Groupings supported by Build
+const (
+	GroupSection = "section"
+	GroupTag     = "tag"
+	GroupNone    = "none"
+)
+
+// DefaultWidth is the terminal width used by Render when none is given
+const DefaultWidth = 120
+
+// Lane names for tasks that have no section or no tag
+const (
+	NoSection = "(no section)"
+	NoTag     = "(untagged)"
+)
+
+// Columns are the board columns in order
+var Columns = []string{"Open", "Worked", "Blocked", "Done"}
+
+// Card is a task on the board. Subtasks are not cards of their own; their
+// progress is shown on their parent's card.
+type Card struct {
+	ID           string
+	Title        string
+	Active       bool
+	Priority     task.Priority
+	Effort       int
+	Subtasks     int
+	SubtasksDone int
+}
+
+// Column is a board column; its WIP count is the number of cards
+type Column struct {
+	Name   string
+	Cards  []Card
+	Effort int // sum of the effort of its cards
+}
+
+// Lane is a group of tasks laid out in the board columns
+type Lane struct {
+	Name    string // section or tag, empty when the board is not grouped
+	Columns []Column
+}
+
+// Total is the WIP count and effort of a column over all lanes
+type Total struct {
+	Name   string
+	Count  int
+	Effort int
+}
+
+// Board holds the lanes and the column totals over all tasks. With tag
+// grouping a task is in the lane of each of its tags, but counted once in
+// Totals.
+type Board struct {
+	Lanes  []Lane
+	Totals []Total
+}
+
+// Build lays out the top-level tasks in columns by status: [ ] and other
+// statuses are Open, [w] Worked, [b] Blocked and [x] Done. Tasks are grouped
+// into lanes by section (in file order), by tag (alphabetically, untagged
+// last) or not at all.
+func Build(tasks []task.Task, groupBy string) (*Board, error) {
+	switch groupBy {
+	case GroupSection, GroupTag, GroupNone, "":
+	default:
+		return nil, fmt.Errorf("unknown grouping '%s' (use section, tag or none)", groupBy)
+	}
+
+	subtasks := make(map[string]int)
+	subtasksDone := make(map[string]int)
+	for _, t := range tasks {
+		if t.Subtask && t.ParentID != "" {
+			subtasks[t.ParentID]++
+			if strings.EqualFold(t.Status, "x") {
+				subtasksDone[t.ParentID]++
+			}
+		}
+	}
+
+	board := &Board{Totals: make([]Total, len(Columns))}
+	for i, name := range Columns {
+		board.Totals[i].Name = name
+	}
+	lanes := make(map[string]int) // lane name -> index in board.Lanes
+	var tagNames []string
+	addCard := func(name string, column int, card Card) {
+		index, ok := lanes[name]
+		if !ok {
+			index = len(board.Lanes)
+			lanes[name] = index
+			board.Lanes = append(board.Lanes, Lane{Name: name, Columns: newColumns()})
+			if groupBy == GroupTag && name != NoTag {
+				tagNames = append(tagNames, name)
+			}
+		}
+		col := &board.Lanes[index].Columns[column]
+		col.Cards = append(col.Cards, card)
+		col.Effort += card.Effort
+	}
+
+	for _, t := range tasks {
+		if t.Subtask {
+			continue
+		}
+		card := Card{
+			ID:           t.ID,
+			Title:        t.Title,
+			Active:       t.Active,
+			Priority:     t.Priority,
+			Effort:       t.Effort,
+			Subtasks:     subtasks[t.ID],
+			SubtasksDone: subtasksDone[t.ID],
+		}
+		column := columnIndex(t.Status)
+		board.Totals[column].Count++
+		board.Totals[column].Effort += t.Effort
+
+		switch groupBy {
+		case GroupSection:
+			name := t.Section
+			if name == "" {
+				name = NoSection
+			}
+			addCard(name, column, card)
+		case GroupTag:
+			seen := make(map[string]bool)
+			for _, tag := range t.Tags {
+				tag = strings.ToLower(tag)
+				if !seen[tag] {
+					seen[tag] = true
+					addCard(tag, column, card)
+				}
+			}
+			if len(seen) == 0 {
+				addCard(NoTag, column, card)
+			}
+		default:
+			addCard("", column, card)
+		}
+	}
+
+	if groupBy == GroupTag {
+		sort.Strings(tagNames)
+		if _, ok := lanes[NoTag]; ok {
+			tagNames = append(tagNames, NoTag)
+		}
+		sorted := make([]Lane, 0, len(board.Lanes))
+		for _, name := range tagNames {
+			sorted = append(sorted, board.Lanes[lanes[name]])
+		}
+		board.Lanes = sorted
+	}
+	return board, nil
+}
+
+// newColumns returns the empty board columns
+func newColumns() []Column {
+	columns := make([]Column, len(Columns))
+	for i, name := range Columns {
+		columns[i].Name = name
+	}
+	return columns
+}
+
+// columnIndex returns the column of a status character
+func columnIndex(status string) int {
+	switch strings.ToLower(status) {
+	case "w":
+		return 1
+	case "b":
+		return 2
+	case "x":
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Render formats the board as columns for a terminal of the given width, or
+// as markdown tables, one per lane. Both end with the totals when the board
+// has more than one lane.
+func Render(board *Board, format string, width int) (string, error) {
+	var render func(*strings.Builder, Lane)
+	switch format {
+	case FormatText, "":
+		if width <= 0 {
+			width = DefaultWidth
+		}
+		render = func(b *strings.Builder, lane Lane) { renderText(b, lane, width) }
+	case FormatMarkdown, "md":
+		render = renderMarkdown
+	default:
+		return "", fmt.Errorf("unknown output format '%s' (use text or markdown)", format)
+	}
+
+	var b strings.Builder
+	for i, lane := range board.Lanes {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		render(&b, lane)
+	}
+	if len(board.Lanes) == 0 {
+		render(&b, Lane{Columns: newColumns()})
+	}
+	if len(board.Lanes) > 1 {
+		var totals []string
+		for _, total := range board.Totals {
+			totals = append(totals, header(total.Name, total.Count, total.Effort))
+		}
+		fmt.Fprintf(&b, "\nTotal: %s\n", strings.Join(totals, ", "))
+	}
+	return b.String(), nil
+}
+
+// renderText writes a lane as side-by-side columns
+func renderText(b *strings.Builder, lane Lane, width int) {
+	const separator = " | "
+	columnWidth := (width - len(separator)*(len(Columns)-1)) / len(Columns)
+	if columnWidth < 10 {
+		columnWidth = 10
+	}
+
+	if lane.Name != "" {
+		b.WriteString(lane.Name + "\n")
+		b.WriteString(strings.Repeat("=", utf8.RuneCountInString(lane.Name)) + "\n")
+	}
+	cells := make([]string, len(lane.Columns))
+	counts := make([]string, len(lane.Columns))
+	rules := make([]string, len(lane.Columns))
+	for i, column := range lane.Columns {
+		cells[i] = fit(column.Name, columnWidth)
+		counts[i] = fit(summary(len(column.Cards), column.Effort), columnWidth)
+		rules[i] = strings.Repeat("-", columnWidth)
+	}
+	writeRow(b, cells, separator)
+	writeRow(b, counts, separator)
+	writeRow(b, rules, "-+-")
+	for row := 0; row < laneHeight(lane); row++ {
+		for i, column := range lane.Columns {
+			cells[i] = fit("", columnWidth)
+			if row < len(column.Cards) {
+				cells[i] = fit(cardText(column.Cards[row]), columnWidth)
+			}
+		}
+		writeRow(b, cells, separator)
+	}
+}
+
+// writeRow writes cells joined by separator without trailing spaces
+func writeRow(b *strings.Builder, cells []string, separator string) {
+	b.WriteString(strings.TrimRight(strings.Join(cells, separator), " ") + "\n")
+}
+
+// renderMarkdown writes a lane as a markdown table with a column per status
+func renderMarkdown(b *strings.Builder, lane Lane) {
+	if lane.Name != "" {
+		fmt.Fprintf(b, "### %s\n\n", lane.Name)
+	}
+	headers := make([]string, len(lane.Columns))
+	rules := make([]string, len(lane.Columns))
+	for i, column := range lane.Columns {
+		headers[i] = header(column.Name, len(column.Cards), column.Effort)
+		rules[i] = "---"
+	}
+	b.WriteString("| " + strings.Join(headers, " | ") + " |\n")
+	b.WriteString("| " + strings.Join(rules, " | ") + " |\n")
+	for row := 0; row < laneHeight(lane); row++ {
+		cells := make([]string, len(lane.Columns))
+		for i, column := range lane.Columns {
+			if row < len(column.Cards) {
+				cells[i] = strings.ReplaceAll(cardText(column.Cards[row]), "|", `\|`)
+			}
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+}
+
+// laneHeight returns the number of cards in the fullest column of a lane
+func laneHeight(lane Lane) int {
+	height := 0
+	for _, column := range lane.Columns {
+		if len(column.Cards) > height {
+			height = len(column.Cards)
+		}
+	}
+	return height
+}
+
+// header returns a column name with its WIP count and effort total
+func header(name string, count, effort int) string {
+	return name + " (" + summary(count, effort) + ")"
+}
+
+// summary returns the WIP count and effort total of a column
+func summary(count, effort int) string {
+	return fmt.Sprintf("WIP %d, effort %d", count, effort)
+}
+
+// cardText returns a card as its active marker, priority/effort code, title
+// and subtask progress
+func cardText(card Card) string {
+	var parts []string
+	if card.Active {
+		parts = append(parts, "!!")
+	}
+	if code := card.Priority.Code(card.Effort); code != "" && card.Effort > 0 {
+		parts = append(parts, code)
+	}
+	parts = append(parts, card.Title)
+	if card.Subtasks > 0 {
+		parts = append(parts, fmt.Sprintf("(%d/%d)", card.SubtasksDone, card.Subtasks))
+	}
+	return strings.Join(parts, " ")
+}
+
+// fit pads or truncates s to width runes, marking truncation with "…"
+func fit(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
@@ -0,0 +1,122 @@
+package board
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+var testLines = []string{
+	"- [ ] Loose end",
+	"## Work",
+	"- [ ] !! A2 Write report #docs #q1",
+	"  - [x] gather numbers",
+	"  - [ ] draft",
+	"- [w] B3 Review | merge PR #code",
+	"- [b] C1 Deploy #ops",
+	"- [x] D5 Ship release #q1",
+	"## Home",
+	"- [w] Water plants",
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name    string
+		groupBy string
+		lanes   []string
+		counts  [][]int // cards per column of each lane
+	}{
+		{"section", GroupSection, []string{NoSection, "Work", "Home"}, [][]int{{1, 0, 0, 0}, {1, 1, 1, 1}, {0, 1, 0, 0}}},
+		{"tag", GroupTag, []string{"code", "docs", "ops", "q1", NoTag}, [][]int{{0, 1, 0, 0}, {1, 0, 0, 0}, {0, 0, 1, 0}, {1, 0, 0, 1}, {1, 1, 0, 0}}},
+		{"none", GroupNone, []string{""}, [][]int{{2, 2, 1, 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := Build(task.Parse(testLines), tt.groupBy)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if len(board.Lanes) != len(tt.lanes) {
+				t.Fatalf("got %d lanes, want %d: %+v", len(board.Lanes), len(tt.lanes), board.Lanes)
+			}
+			for i, lane := range board.Lanes {
+				if lane.Name != tt.lanes[i] {
+					t.Errorf("lane %d = %q, want %q", i, lane.Name, tt.lanes[i])
+				}
+				for j, column := range lane.Columns {
+					if len(column.Cards) != tt.counts[i][j] {
+						t.Errorf("lane %q column %s has %d cards, want %d", lane.Name, column.Name, len(column.Cards), tt.counts[i][j])
+					}
+				}
+			}
+
+			// totals count each task once, whatever the grouping
+			want := []Total{{"Open", 2, 2}, {"Worked", 2, 3}, {"Blocked", 1, 1}, {"Done", 1, 5}}
+			for i, total := range board.Totals {
+				if total != want[i] {
+					t.Errorf("Totals[%d] = %+v, want %+v", i, total, want[i])
+				}
+			}
+		})
+	}
+
+	if _, err := Build(nil, "owner"); err == nil {
+		t.Error("Build() with an unknown grouping should fail")
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	board, err := Build(task.Parse(testLines[1:8]), GroupSection)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	got, err := Render(board, FormatMarkdown, 0)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := "### Work\n\n" +
+		"| Open (WIP 1, effort 2) | Worked (WIP 1, effort 3) | Blocked (WIP 1, effort 1) | Done (WIP 1, effort 5) |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| !! A2 Write report #docs #q1 (1/2) | B3 Review \\| merge PR #code | C1 Deploy #ops | D5 Ship release #q1 |\n"
+	if got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	board, err := Build(task.Parse(testLines), GroupSection)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	got, err := Render(board, FormatText, 80)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	for _, expected := range []string{
+		"Work\n====\n",
+		"Open              | Worked            | Blocked           | Done\n" +
+			"WIP 1, effort 2   | WIP 1, effort 3   | WIP 1, effort 1   | WIP 1, effort 5\n" +
+			"------------------+-------------------+-------------------+------------------\n" +
+			"!! A2 Write repo… | B3 Review | merg… | C1 Deploy #ops    | D5 Ship release …\n",
+		"                  | Water plants      |                   |\n",
+		"Total: Open (WIP 2, effort 2), Worked (WIP 2, effort 3), Blocked (WIP 1, effort 1), Done (WIP 1, effort 5)\n",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("Render() does not contain %q:\n%s", expected, got)
+		}
+	}
+	for _, line := range strings.Split(got, "\n") {
+		if utf8.RuneCountInString(line) > 80 && !strings.HasPrefix(line, "Total:") {
+			t.Errorf("line is wider than the terminal: %q", line)
+		}
+	}
+
+	if _, err := Render(board, "html", 80); err == nil {
+		t.Error("Render() with an unknown format should fail")
+	}
+}
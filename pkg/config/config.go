@@ -6,6 +6,9 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+	"github.com/robertarles/taskmasterra/v2/pkg/reminder"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
 	"github.com/robertarles/taskmasterra/v2/pkg/utils"
 )
 
@@ -16,10 +19,56 @@ type Config struct {
 	DefaultDueMinute int    `json:"default_due_minute"`
 	ReminderListName string `json:"reminder_list_name"`
 
+	// ReminderLists sends the tasks of a section or with a tag to other
+	// reminder lists, which are created when missing. The first matching
+	// rule wins; other tasks go to ReminderListName.
+	ReminderLists reminder.ListRules `json:"reminder_lists"`
+
+	// DuePriorities lists the priorities (A-D or names such as "High") of
+	// active tasks that are due today when the task has no date of its own.
+	// IgnoreTaskDates stops a task's org-mode date from setting its due date.
+	DuePriorities   []string `json:"due_priorities"`
+	IgnoreTaskDates bool     `json:"ignore_task_dates"`
+
+	// ReminderBackend selects where active tasks are synced; empty means
+	// "reminders" (macOS Reminders.app)
+	ReminderBackend string `json:"reminder_backend"`
+
+	// CalDAV settings for the "caldav" backend. CalDAVURL is the calendar
+	// collection that VTODOs are synced to. The password may instead be set
+	// in the TASKMASTERRA_CALDAV_PASSWORD environment variable.
+	CalDAVURL      string `json:"caldav_url"`
+	CalDAVUsername string `json:"caldav_username"`
+	CalDAVPassword string `json:"caldav_password"`
+
+	// NotifyMethod selects how the notify command sends desktop notifications
+	// on Linux: "notify-send" (the default) or "dbus"
+	NotifyMethod string `json:"notify_method"`
+
 	// Journal settings
 	JournalSuffix string `json:"journal_suffix"`
 	ArchiveSuffix string `json:"archive_suffix"`
 
+	// Timestamp settings for journal and archive entries.
+	// Timezone is an IANA name (e.g. "America/Denver") or "Local"; empty means UTC.
+	// TimestampLayout is a Go time layout; empty means "2006-01-02 15:04:05 MST".
+	Timezone        string `json:"timezone"`
+	TimestampLayout string `json:"timestamp_layout"`
+
+	// JournalLayout is "prepend" (newest entries first) or "append" (oldest
+	// first, written without rewriting the file); empty means "prepend".
+	JournalLayout string `json:"journal_layout"`
+
+	// Snapshot settings. A snapshot of the todo file is saved before any
+	// command modifies it; zero keep values disable that limit.
+	DisableSnapshots  bool `json:"disable_snapshots"`
+	SnapshotKeepCount int  `json:"snapshot_keep_count"`
+	SnapshotKeepDays  int  `json:"snapshot_keep_days"`
+
+	// GitAutoCommit commits the todo, journal and archive files after
+	// recordkeep when the todo file is inside a git repository.
+	GitAutoCommit bool `json:"git_auto_commit"`
+
 	// File settings
 	DefaultFilePermissions os.FileMode `json:"default_file_permissions"`
 
@@ -33,8 +82,15 @@ func DefaultConfig() *Config {
 		DefaultDueHour:        16,
 		DefaultDueMinute:      0,
 		ReminderListName:      "Taskmasterra",
+		DuePriorities:         []string{"A", "B"},
+		ReminderBackend:       reminder.DefaultBackend,
 		JournalSuffix:         ".xjournal.md",
 		ArchiveSuffix:         ".xarchive.md",
+		Timezone:              "UTC",
+		TimestampLayout:       "2006-01-02 15:04:05 MST",
+		JournalLayout:         journal.LayoutPrepend,
+		SnapshotKeepCount:     20,
+		SnapshotKeepDays:      30,
 		DefaultFilePermissions: 0644,
 		ActiveMarker:          "!!",
 	}
@@ -67,12 +123,14 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read configuration file '%s': %w", configPath, err)
 	}
 
-	var config Config
-	if err := json.Unmarshal([]byte(content), &config); err != nil {
+	// Start from the defaults so that settings added in later releases
+	// have sensible values in older configuration files
+	config := DefaultConfig()
+	if err := json.Unmarshal([]byte(content), config); err != nil {
 		return nil, fmt.Errorf("failed to parse configuration file '%s' as JSON: %w", configPath, err)
 	}
 
-	return &config, nil
+	return config, nil
 }
 
 // SaveConfig saves configuration to file
@@ -112,6 +170,14 @@ func (c *Config) Validate() error {
 	if c.ReminderListName == "" {
 		return fmt.Errorf("reminder_list_name cannot be empty")
 	}
+	if _, err := c.DuePolicy(); err != nil {
+		return err
+	}
+	for _, rule := range c.ReminderLists {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("reminder_lists is invalid: %w", err)
+		}
+	}
 	if c.JournalSuffix == "" {
 		return fmt.Errorf("journal_suffix cannot be empty")
 	}
@@ -121,5 +187,84 @@ func (c *Config) Validate() error {
 	if c.ActiveMarker == "" {
 		return fmt.Errorf("active_marker cannot be empty")
 	}
+	// Checks both the timezone and the layout
+	if _, err := journal.NewTimestampFormat(c.Timezone, c.TimestampLayout); err != nil {
+		return fmt.Errorf("timezone or timestamp_layout is invalid: %w", err)
+	}
+	if c.SnapshotKeepCount < 0 {
+		return fmt.Errorf("snapshot_keep_count cannot be negative (got %d)", c.SnapshotKeepCount)
+	}
+	if c.SnapshotKeepDays < 0 {
+		return fmt.Errorf("snapshot_keep_days cannot be negative (got %d)", c.SnapshotKeepDays)
+	}
+	if c.JournalLayout != "" && !journal.ValidLayout(c.JournalLayout) {
+		return fmt.Errorf("journal_layout must be '%s' or '%s' (got '%s')", journal.LayoutPrepend, journal.LayoutAppend, c.JournalLayout)
+	}
+
+	if c.ReminderBackend != "" && !containsString(reminder.BackendNames(), c.ReminderBackend) {
+		return fmt.Errorf("reminder_backend must be one of %v (got '%s')", reminder.BackendNames(), c.ReminderBackend)
+	}
+
+	if _, err := reminder.NewNotifier(c.NotifyMethod); err != nil {
+		return fmt.Errorf("notify_method is invalid: %w", err)
+	}
+
+	if c.ReminderBackend == reminder.BackendCalDAV && c.CalDAVURL == "" {
+		return fmt.Errorf("caldav_url is required when reminder_backend is '%s'", reminder.BackendCalDAV)
+	}
 	return nil
-} 
\ No newline at end of file
+}
+
+// JournalTimestampFormat returns the timestamp format for journal and archive entries
+func (c *Config) JournalTimestampFormat() (journal.TimestampFormat, error) {
+	return journal.NewTimestampFormat(c.Timezone, c.TimestampLayout)
+}
+
+// containsString reports whether values contains target
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// ReminderOptions returns the options for the configured reminder backend
+func (c *Config) ReminderOptions() reminder.Options {
+	password := c.CalDAVPassword
+	if env := os.Getenv("TASKMASTERRA_CALDAV_PASSWORD"); env != "" {
+		password = env
+	}
+	// An invalid policy is reported by Validate; fall back to the defaults here
+	policy, err := c.DuePolicy()
+	if err != nil {
+		policy = reminder.DefaultDuePolicy()
+	}
+	return reminder.Options{
+		ListName:  c.ReminderListName,
+		Lists:     c.ReminderLists.Lists(),
+		DuePolicy: policy,
+		URL:       c.CalDAVURL,
+		Username:  c.CalDAVUsername,
+		Password:  password,
+	}
+}
+
+// DuePolicy returns the due-date policy for reminders built from the
+// default due time, due priorities and task date settings
+func (c *Config) DuePolicy() (reminder.DuePolicy, error) {
+	policy := reminder.DuePolicy{
+		Hour:            c.DefaultDueHour,
+		Minute:          c.DefaultDueMinute,
+		IgnoreTaskDates: c.IgnoreTaskDates,
+	}
+	for _, name := range c.DuePriorities {
+		priority, ok := task.ParsePriorityName(name)
+		if !ok {
+			return policy, fmt.Errorf("due_priorities contains unknown priority '%s' (use A-D or Critical, High, Medium, Low)", name)
+		}
+		policy.Priorities = append(policy.Priorities, priority)
+	}
+	return policy, nil
+}
@@ -5,6 +5,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/reminder"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -125,6 +128,34 @@ func TestLoadConfig_ExistingFile(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_PartialFile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "config-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// A config file written before newer settings existed
+	configPath := filepath.Join(tmpDir, "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"reminder_list_name": "Old"}`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	loadedConfig, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if loadedConfig.ReminderListName != "Old" {
+		t.Errorf("Expected ReminderListName to be 'Old', got %s", loadedConfig.ReminderListName)
+	}
+	if loadedConfig.SnapshotKeepCount != DefaultConfig().SnapshotKeepCount {
+		t.Errorf("Missing settings should keep their defaults, got SnapshotKeepCount %d", loadedConfig.SnapshotKeepCount)
+	}
+	if err := loadedConfig.Validate(); err != nil {
+		t.Errorf("Partial config should be valid: %v", err)
+	}
+}
+
 func TestSaveConfig(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "config-test-*")
 	if err != nil {
@@ -204,6 +235,64 @@ func TestConfigValidate(t *testing.T) {
 			wantErr: true,
 			msg:    "active_marker",
 		},
+		{
+			name:    "Local timezone and custom layout",
+			cfg:     Config{DefaultDueHour: 10, DefaultDueMinute: 0, ReminderListName: "List", JournalSuffix: ".xjournal.md", ArchiveSuffix: ".xarchive.md", ActiveMarker: "!!", Timezone: "Local", TimestampLayout: "2006-01-02 15:04 -0700"},
+			wantErr: false,
+		},
+		{
+			name:    "Invalid timezone",
+			cfg:     Config{DefaultDueHour: 10, DefaultDueMinute: 0, ReminderListName: "List", JournalSuffix: ".xjournal.md", ArchiveSuffix: ".xarchive.md", ActiveMarker: "!!", Timezone: "Nowhere/Special"},
+			wantErr: true,
+			msg:     "timezone",
+		},
+		{
+			name:    "Timestamp layout without date",
+			cfg:     Config{DefaultDueHour: 10, DefaultDueMinute: 0, ReminderListName: "List", JournalSuffix: ".xjournal.md", ArchiveSuffix: ".xarchive.md", ActiveMarker: "!!", TimestampLayout: "15:04"},
+			wantErr: true,
+			msg:     "timestamp_layout",
+		},
+		{
+			name:    "Append journal layout",
+			cfg:     Config{DefaultDueHour: 10, DefaultDueMinute: 0, ReminderListName: "List", JournalSuffix: ".xjournal.md", ArchiveSuffix: ".xarchive.md", ActiveMarker: "!!", JournalLayout: "append"},
+			wantErr: false,
+		},
+		{
+			name:    "Unknown journal layout",
+			cfg:     Config{DefaultDueHour: 10, DefaultDueMinute: 0, ReminderListName: "List", JournalSuffix: ".xjournal.md", ArchiveSuffix: ".xarchive.md", ActiveMarker: "!!", JournalLayout: "sideways"},
+			wantErr: true,
+			msg:     "journal_layout",
+		},
+		{
+			name:    "Unknown reminder backend",
+			cfg:     Config{DefaultDueHour: 10, DefaultDueMinute: 0, ReminderListName: "List", JournalSuffix: ".xjournal.md", ArchiveSuffix: ".xarchive.md", ActiveMarker: "!!", ReminderBackend: "palm-pilot"},
+			wantErr: true,
+			msg:     "reminder_backend",
+		},
+		{
+			name:    "CalDAV backend without URL",
+			cfg:     Config{DefaultDueHour: 10, DefaultDueMinute: 0, ReminderListName: "List", JournalSuffix: ".xjournal.md", ArchiveSuffix: ".xarchive.md", ActiveMarker: "!!", ReminderBackend: "caldav"},
+			wantErr: true,
+			msg:     "caldav_url",
+		},
+		{
+			name:    "Reminder list rule without list",
+			cfg:     Config{DefaultDueHour: 10, DefaultDueMinute: 0, ReminderListName: "List", JournalSuffix: ".xjournal.md", ArchiveSuffix: ".xarchive.md", ActiveMarker: "!!", ReminderLists: reminder.ListRules{{Section: "## Work"}}},
+			wantErr: true,
+			msg:     "reminder_lists",
+		},
+		{
+			name:    "Unknown due priority",
+			cfg:     Config{DefaultDueHour: 10, DefaultDueMinute: 0, ReminderListName: "List", JournalSuffix: ".xjournal.md", ArchiveSuffix: ".xarchive.md", ActiveMarker: "!!", DuePriorities: []string{"A", "urgent"}},
+			wantErr: true,
+			msg:     "due_priorities",
+		},
+		{
+			name:    "Negative snapshot retention",
+			cfg:     Config{DefaultDueHour: 10, DefaultDueMinute: 0, ReminderListName: "List", JournalSuffix: ".xjournal.md", ArchiveSuffix: ".xarchive.md", ActiveMarker: "!!", SnapshotKeepCount: -1},
+			wantErr: true,
+			msg:     "snapshot_keep_count",
+		},
 	}
 
 	for _, c := range cases {
@@ -222,4 +311,50 @@ func TestConfigValidate(t *testing.T) {
 			}
 		})
 	}
-} 
\ No newline at end of file
+} 
+func TestDuePolicy(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DefaultDueHour = 9
+	cfg.DefaultDueMinute = 30
+	cfg.DuePriorities = []string{"a", "Medium"}
+	cfg.IgnoreTaskDates = true
+
+	policy, err := cfg.DuePolicy()
+	if err != nil {
+		t.Fatalf("DuePolicy() error = %v", err)
+	}
+	if policy.Hour != 9 || policy.Minute != 30 || !policy.IgnoreTaskDates {
+		t.Errorf("DuePolicy() = %+v, want 09:30 ignoring task dates", policy)
+	}
+	if len(policy.Priorities) != 2 || policy.Priorities[0] != task.PriorityCritical || policy.Priorities[1] != task.PriorityMedium {
+		t.Errorf("DuePolicy().Priorities = %v, want [Critical Medium]", policy.Priorities)
+	}
+	if opts := cfg.ReminderOptions(); opts.DuePolicy.Hour != 9 {
+		t.Errorf("ReminderOptions().DuePolicy = %+v, want the configured policy", opts.DuePolicy)
+	}
+}
+
+func TestLoadConfig_ReminderLists(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "config-lists-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	configPath := filepath.Join(tmpDir, "config.json")
+	content := `{"reminder_lists": [{"section": "## Work", "list": "Work"}, {"tag": "#ferris", "list": "Homelab"}]}`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if opts := cfg.ReminderOptions(); strings.Join(opts.Lists, ",") != "Work,Homelab" || opts.ListName != "Taskmasterra" {
+		t.Errorf("ReminderOptions() = %+v, want the default list and Work, Homelab", opts)
+	}
+}
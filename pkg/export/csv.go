@@ -0,0 +1,93 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+// CSVHeader is the header row of a CSV export
+var CSVHeader = []string{
+	"source", "timestamp", "section", "status", "state", "active", "subtask",
+	"priority", "effort", "tags", "title", "date", "id",
+}
+
+// CSVOptions selects the history written by CSV
+type CSVOptions struct {
+	Journal []journal.Entry // one row per task worked on, stamped with the journal time
+	Archive []journal.Entry // one row per archived task, stamped with the archive time
+}
+
+// CSV returns the tasks as CSV with a CSVHeader row, one row per task or
+// subtask: first the todo file (source "todo", no timestamp), then the journal
+// and the archive. Timestamps are written as "2006-01-02 15:04" and dates
+// without their weekday or repeater so spreadsheets read them as dates; tags
+// are separated by spaces and effort is always a number. Cells starting with
+// =, +, - or @ are prefixed with ' so spreadsheets do not run them as
+// formulas.
+func CSV(tasks []task.Task, opts CSVOptions) (string, error) {
+	rows := [][]string{CSVHeader}
+	for _, t := range tasks {
+		rows = append(rows, csvRow("todo", "", t.Section, t))
+	}
+	for _, history := range []struct {
+		source  string
+		entries []journal.Entry
+	}{{journal.SourceJournal, opts.Journal}, {journal.SourceArchive, opts.Archive}} {
+		for _, entry := range history.entries {
+			timestamp := entry.Timestamp.Format("2006-01-02 15:04")
+			for _, t := range task.Parse(append([]string{entry.Line}, entry.Details...)) {
+				rows = append(rows, csvRow(history.source, timestamp, entry.Section, t))
+			}
+		}
+	}
+
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if err := w.WriteAll(rows); err != nil {
+		return "", fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return b.String(), nil
+}
+
+// csvRow returns the CSV fields of a task
+func csvRow(source, timestamp, section string, t task.Task) []string {
+	date := ""
+	if t.Date != nil {
+		date = t.Date.Time.Format("2006-01-02")
+		if t.Date.HasTime {
+			date = t.Date.Time.Format("2006-01-02 15:04")
+		}
+	}
+	row := []string{
+		source,
+		timestamp,
+		section,
+		t.Status,
+		State(t.Status),
+		strconv.FormatBool(t.Active),
+		strconv.FormatBool(t.Subtask),
+		t.Priority.String(),
+		strconv.Itoa(t.Effort),
+		strings.Join(t.Tags, " "),
+		t.Title,
+		date,
+		t.ID,
+	}
+	for i, cell := range row {
+		row[i] = csvCell(cell)
+	}
+	return row
+}
+
+// csvCell prefixes a cell that a spreadsheet would read as a formula with '
+func csvCell(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
@@ -0,0 +1,86 @@
+package export
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+func TestCSV(t *testing.T) {
+	lines := []string{
+		"## Work",
+		"- [w] !! A2 Write \"Q1\" report, draft #docs #q1 <2024-03-09 Sat 14:00>",
+		"  - [ ] gather numbers",
+	}
+	at := time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)
+	opts := CSVOptions{
+		Journal: []journal.Entry{{Timestamp: at, Line: "- [w] !! A2 Write \"Q1\" report, draft #docs #q1 <2024-03-09 Sat 14:00>", Section: "Work"}},
+		Archive: []journal.Entry{{
+			Timestamp: at.Add(24 * time.Hour),
+			Line:      "- [x] D3 Renew passport",
+			Details:   []string{"  - [x] fill form"},
+			Section:   "Travel",
+		}},
+	}
+
+	output, err := CSV(task.Parse(lines), opts)
+	if err != nil {
+		t.Fatalf("CSV() error = %v", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v\n%s", err, output)
+	}
+
+	// all columns but the task ID
+	want := [][]string{
+		{"source", "timestamp", "section", "status", "state", "active", "subtask", "priority", "effort", "tags", "title", "date"},
+		{"todo", "", "Work", "w", "worked", "true", "false", "Critical", "2", "docs q1", "Write \"Q1\" report, draft #docs #q1", "2024-03-09 14:00"},
+		{"todo", "", "Work", " ", "open", "false", "true", "None", "0", "", "gather numbers", ""},
+		{"journal", "2024-03-04 09:30", "Work", "w", "worked", "true", "false", "Critical", "2", "docs q1", "Write \"Q1\" report, draft #docs #q1", "2024-03-09 14:00"},
+		{"archive", "2024-03-05 09:30", "Travel", "x", "completed", "false", "false", "Low", "3", "", "Renew passport", ""},
+		{"archive", "2024-03-05 09:30", "Travel", "x", "completed", "false", "true", "None", "0", "", "fill form", ""},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d:\n%s", len(rows), len(want), output)
+	}
+	for i := range want {
+		got := rows[i][:len(rows[i])-1]
+		if strings.Join(got, "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %q, want %q", i, got, want[i])
+		}
+	}
+	if id := rows[1][len(rows[1])-1]; id == "" || id != rows[3][len(rows[3])-1] {
+		t.Errorf("todo and journal rows of the same task have IDs %q and %q", id, rows[3][len(rows[3])-1])
+	}
+}
+
+func TestCSVEscapesFormulas(t *testing.T) {
+	tasks := task.Parse([]string{
+		"## =HYPERLINK(\"http://example.com\")",
+		"- [ ] =1+2",
+		"- [ ] @SUM(cells) #+tag",
+		"- [ ] plain = text",
+	})
+	output, err := CSV(tasks, CSVOptions{})
+	if err != nil {
+		t.Fatalf("CSV() error = %v", err)
+	}
+	rows, _ := csv.NewReader(strings.NewReader(output)).ReadAll()
+
+	// section and title columns
+	want := [][2]string{
+		{"'=HYPERLINK(\"http://example.com\")", "'=1+2"},
+		{"'=HYPERLINK(\"http://example.com\")", "'@SUM(cells) #+tag"},
+		{"'=HYPERLINK(\"http://example.com\")", "plain = text"},
+	}
+	for i, w := range want {
+		if got := [2]string{rows[i+1][2], rows[i+1][10]}; got != w {
+			t.Errorf("row %d section and title = %q, want %q", i+1, got, w)
+		}
+	}
+}
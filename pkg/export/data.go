@@ -0,0 +1,177 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+// SchemaVersion is the version of the JSON and YAML export schema. It changes
+// only when a field is removed or changes meaning; new fields may be added
+// within a version.
+//
+// Version 1 is a document with these fields:
+//
+//	schema_version  1
+//	source          path of the exported todo file
+//	exported_at     RFC 3339 time of the export
+//	tasks           the tasks and subtasks in file order, each with:
+//	  id            8 hex digit task ID, the same ID shown by 'taskmasterra log'
+//	  line          1-based line number in the todo file
+//	  section       text of the enclosing markdown header, "" before the first one
+//	  status        the raw status character: " ", "w", "b", "x", ...
+//	  state         open, worked, blocked, completed or other, ignoring case
+//	  active        true for tasks marked !!
+//	  subtask       true for indented tasks
+//	  parent_id     ID of the enclosing task, subtasks only
+//	  priority      None, Low, Medium, High or Critical
+//	  effort        effort estimate, 0 when missing
+//	  title         title without status, !! marker, priority/effort code or dates
+//	  tags          tags without the leading #
+//	  date          org-mode timestamp such as "<2024-03-09 Sat .+1w>", omitted when missing
+//	  details       indented detail lines without the leading "- "
+//
+// tags and details are always present, as empty lists when a task has none.
+const SchemaVersion = 1
+
+// Document is an export of the tasks of a todo file
+type Document struct {
+	SchemaVersion int          `json:"schema_version"`
+	Source        string       `json:"source"`
+	ExportedAt    string       `json:"exported_at"`
+	Tasks         []TaskRecord `json:"tasks"`
+}
+
+// TaskRecord is a task in a Document; see SchemaVersion for the field meanings
+type TaskRecord struct {
+	ID       string   `json:"id"`
+	Line     int      `json:"line"`
+	Section  string   `json:"section"`
+	Status   string   `json:"status"`
+	State    string   `json:"state"`
+	Active   bool     `json:"active"`
+	Subtask  bool     `json:"subtask"`
+	ParentID string   `json:"parent_id,omitempty"`
+	Priority string   `json:"priority"`
+	Effort   int      `json:"effort"`
+	Title    string   `json:"title"`
+	Tags     []string `json:"tags"`
+	Date     string   `json:"date,omitempty"`
+	Details  []string `json:"details"`
+}
+
+// NewDocument returns the export document for the tasks of the todo file at source
+func NewDocument(tasks []task.Task, source string, now time.Time) Document {
+	doc := Document{
+		SchemaVersion: SchemaVersion,
+		Source:        source,
+		ExportedAt:    now.Format(time.RFC3339),
+		Tasks:         make([]TaskRecord, 0, len(tasks)),
+	}
+	for _, t := range tasks {
+		record := TaskRecord{
+			ID:       t.ID,
+			Line:     t.LineNumber,
+			Section:  t.Section,
+			Status:   t.Status,
+			State:    State(t.Status),
+			Active:   t.Active,
+			Subtask:  t.Subtask,
+			ParentID: t.ParentID,
+			Priority: t.Priority.String(),
+			Effort:   t.Effort,
+			Title:    t.Title,
+			Tags:     append([]string{}, t.Tags...),
+			Details:  append([]string{}, t.Details...),
+		}
+		if t.Date != nil {
+			record.Date = t.Date.String()
+		}
+		doc.Tasks = append(doc.Tasks, record)
+	}
+	return doc
+}
+
+// State returns the name of a status character
+func State(status string) string {
+	switch strings.ToLower(status) {
+	case " ", "":
+		return "open"
+	case "w":
+		return "worked"
+	case "b":
+		return "blocked"
+	case "x":
+		return "completed"
+	default:
+		return "other"
+	}
+}
+
+// JSON returns the document as indented JSON
+func JSON(doc Document) (string, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal tasks to JSON: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// YAML returns the document as YAML with the same field names as JSON.
+// Strings are always double-quoted so values such as "no" or "1.0" keep
+// their type.
+func YAML(doc Document) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "schema_version: %d\n", doc.SchemaVersion)
+	fmt.Fprintf(&b, "source: %s\n", yamlString(doc.Source))
+	fmt.Fprintf(&b, "exported_at: %s\n", yamlString(doc.ExportedAt))
+	if len(doc.Tasks) == 0 {
+		b.WriteString("tasks: []\n")
+		return b.String()
+	}
+
+	b.WriteString("tasks:\n")
+	for _, t := range doc.Tasks {
+		fmt.Fprintf(&b, "  - id: %s\n", yamlString(t.ID))
+		fmt.Fprintf(&b, "    line: %d\n", t.Line)
+		fmt.Fprintf(&b, "    section: %s\n", yamlString(t.Section))
+		fmt.Fprintf(&b, "    status: %s\n", yamlString(t.Status))
+		fmt.Fprintf(&b, "    state: %s\n", yamlString(t.State))
+		fmt.Fprintf(&b, "    active: %t\n", t.Active)
+		fmt.Fprintf(&b, "    subtask: %t\n", t.Subtask)
+		if t.ParentID != "" {
+			fmt.Fprintf(&b, "    parent_id: %s\n", yamlString(t.ParentID))
+		}
+		fmt.Fprintf(&b, "    priority: %s\n", yamlString(t.Priority))
+		fmt.Fprintf(&b, "    effort: %d\n", t.Effort)
+		fmt.Fprintf(&b, "    title: %s\n", yamlString(t.Title))
+		writeYAMLList(&b, "tags", t.Tags)
+		if t.Date != "" {
+			fmt.Fprintf(&b, "    date: %s\n", yamlString(t.Date))
+		}
+		writeYAMLList(&b, "details", t.Details)
+	}
+	return b.String()
+}
+
+// writeYAMLList writes a task field holding a list of strings
+func writeYAMLList(b *strings.Builder, key string, values []string) {
+	if len(values) == 0 {
+		fmt.Fprintf(b, "    %s: []\n", key)
+		return
+	}
+	fmt.Fprintf(b, "    %s:\n", key)
+	for _, value := range values {
+		fmt.Fprintf(b, "      - %s\n", yamlString(value))
+	}
+}
+
+// yamlString quotes s as a YAML double-quoted scalar. The escapes produced by
+// strconv.Quote are a subset of the YAML ones.
+func yamlString(s string) string {
+	return strconv.Quote(s)
+}
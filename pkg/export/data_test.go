@@ -0,0 +1,130 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+func TestNewDocument(t *testing.T) {
+	lines := []string{
+		"## Work",
+		"- [ ] !! A2 Ship release #work <2024-03-09 Sat .+1w>",
+		"  check changelog",
+		"  - [x] tag build",
+		"- [B] C3 Waiting on review",
+	}
+	now := time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC)
+
+	doc := NewDocument(task.Parse(lines), "/notes/todo.md", now)
+
+	if doc.SchemaVersion != SchemaVersion || doc.Source != "/notes/todo.md" || doc.ExportedAt != "2024-03-05T08:00:00Z" {
+		t.Errorf("document header = %+v", doc)
+	}
+	if len(doc.Tasks) != 3 {
+		t.Fatalf("got %d tasks, want 3: %+v", len(doc.Tasks), doc.Tasks)
+	}
+
+	first := doc.Tasks[0]
+	if first.ID != task.ID(lines[1]) || first.Line != 2 || first.Section != "Work" || first.State != "open" || !first.Active {
+		t.Errorf("first task = %+v", first)
+	}
+	if first.Priority != "Critical" || first.Effort != 2 || first.Title != "Ship release #work" {
+		t.Errorf("first task = %+v", first)
+	}
+	if first.Date != "<2024-03-09 Sat .+1w>" || len(first.Tags) != 1 || first.Tags[0] != "work" || len(first.Details) != 1 {
+		t.Errorf("first task = %+v", first)
+	}
+	if sub := doc.Tasks[1]; !sub.Subtask || sub.ParentID != first.ID || sub.State != "completed" {
+		t.Errorf("subtask = %+v", sub)
+	}
+	if last := doc.Tasks[2]; last.Status != "B" || last.State != "blocked" || last.Tags == nil || last.Details == nil {
+		t.Errorf("last task = %+v, want blocked with empty tag and detail lists", last)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	doc := NewDocument(task.Parse([]string{"- [ ] B1 Write \"docs\""}), "todo.md", time.Now())
+
+	data, err := JSON(doc)
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+
+	var decoded Document
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v\n%s", err, data)
+	}
+	if decoded.SchemaVersion != SchemaVersion || len(decoded.Tasks) != 1 || decoded.Tasks[0].Title != `Write "docs"` {
+		t.Errorf("decoded document = %+v", decoded)
+	}
+	for _, field := range []string{`"schema_version": 1`, `"tags": []`, `"details": []`} {
+		if !strings.Contains(data, field) {
+			t.Errorf("JSON() missing %s:\n%s", field, data)
+		}
+	}
+}
+
+func TestYAML(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{
+			name:  "no tasks",
+			lines: nil,
+			want: "schema_version: 1\n" +
+				"source: \"todo.md\"\n" +
+				"exported_at: \"2024-03-05T08:00:00Z\"\n" +
+				"tasks: []\n",
+		},
+		{
+			name:  "task with subtask",
+			lines: []string{"- [w] A1 Fix \"no\" bug #dev", "  - [ ] write test"},
+			want: "schema_version: 1\n" +
+				"source: \"todo.md\"\n" +
+				"exported_at: \"2024-03-05T08:00:00Z\"\n" +
+				"tasks:\n" +
+				"  - id: \"" + task.ID("- [w] A1 Fix \"no\" bug #dev") + "\"\n" +
+				"    line: 1\n" +
+				"    section: \"\"\n" +
+				"    status: \"w\"\n" +
+				"    state: \"worked\"\n" +
+				"    active: false\n" +
+				"    subtask: false\n" +
+				"    priority: \"Critical\"\n" +
+				"    effort: 1\n" +
+				"    title: \"Fix \\\"no\\\" bug #dev\"\n" +
+				"    tags:\n" +
+				"      - \"dev\"\n" +
+				"    details: []\n" +
+				"  - id: \"" + task.ID("  - [ ] write test") + "\"\n" +
+				"    line: 2\n" +
+				"    section: \"\"\n" +
+				"    status: \" \"\n" +
+				"    state: \"open\"\n" +
+				"    active: false\n" +
+				"    subtask: true\n" +
+				"    parent_id: \"" + task.ID("- [w] A1 Fix \"no\" bug #dev") + "\"\n" +
+				"    priority: \"None\"\n" +
+				"    effort: 0\n" +
+				"    title: \"write test\"\n" +
+				"    tags: []\n" +
+				"    details: []\n",
+		},
+	}
+
+	now := time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := YAML(NewDocument(task.Parse(tt.lines), "todo.md", now))
+			if got != tt.want {
+				t.Errorf("YAML() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Package export converts the tasks of a todo file to formats read by other tools.
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/ical"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+// uidSuffix matches the UIDs of VTODOs synced by the CalDAV reminder backend,
+// so a task has the same UID in a subscribed file and a synced collection
+const uidSuffix = "@taskmasterra"
+
+// ICSOptions controls which components ICS writes
+type ICSOptions struct {
+	Name   string // calendar name shown by subscribing apps
+	Todos  bool   // write a VTODO for every exported task
+	Events bool   // write a VEVENT for every exported task with a date
+}
+
+// DefaultICSOptions returns options that write both VTODOs and VEVENTs
+func DefaultICSOptions() ICSOptions {
+	return ICSOptions{Name: "Taskmasterra", Todos: true, Events: true}
+}
+
+// ICS returns an iCalendar file with the open tasks that have a date or the
+// active (!!) marker. Dated tasks are due on their date and repeat by their org
+// repeater; active tasks without a date are due today. now is used for DTSTAMP
+// and as today's date.
+func ICS(tasks []task.Task, now time.Time, opts ICSOptions) string {
+	cal := ical.Calendar{Name: opts.Name}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	occurrences := make(map[string]int) // tasks exported so far by ID
+
+	for _, t := range tasks {
+		if task.IsCompleted(strings.TrimLeft(t.Line, " \t")) || (t.Date == nil && !t.Active) {
+			continue
+		}
+
+		// Tasks with the same title share an ID; later ones are numbered so
+		// every UID in the calendar stays unique
+		uid := t.ID
+		if n := occurrences[t.ID]; n > 0 {
+			uid = fmt.Sprintf("%s-%d", t.ID, n+1)
+		}
+		occurrences[t.ID]++
+
+		description := strings.Join(t.Details, "\n")
+		due, allDay, rrule := today, true, ""
+		if t.Date != nil {
+			due, allDay = t.Date.Time, !t.Date.HasTime
+			if t.Date.Repeater != nil {
+				rrule = t.Date.Repeater.RRule()
+			}
+		}
+
+		if opts.Todos {
+			cal.Todos = append(cal.Todos, ical.Todo{
+				UID:         uid + uidSuffix,
+				Summary:     t.Title,
+				Description: description,
+				Due:         due,
+				AllDay:      allDay,
+				RRule:       rrule,
+				Priority:    t.Priority.ICalendar(),
+				Categories:  t.Tags,
+			})
+		}
+		if opts.Events && t.Date != nil {
+			cal.Events = append(cal.Events, ical.Event{
+				UID:         uid + "-event" + uidSuffix,
+				Summary:     t.Title,
+				Description: description,
+				Start:       due,
+				AllDay:      allDay,
+				RRule:       rrule,
+				Categories:  t.Tags,
+			})
+		}
+	}
+
+	return ical.EncodeCalendar(cal, now)
+}
@@ -0,0 +1,93 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/ical"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+func TestICS(t *testing.T) {
+	lines := []string{
+		"- [ ] !! A1 call the bank",
+		"- [ ] B2 water plants <2021-12-10 Fri .+30d>",
+		"  - the ones on the balcony",
+		"- [ ] D1 standup <2024-03-05 Tue 09:30 +1w>",
+		"- [ ] C3 someday",
+		"- [x] finished <2024-03-01 Fri>",
+	}
+	now := time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC)
+
+	data := ICS(task.Parse(lines), now, DefaultICSOptions())
+
+	todos, err := ical.Parse(data)
+	if err != nil {
+		t.Fatalf("ical.Parse() error = %v", err)
+	}
+	if len(todos) != 3 {
+		t.Fatalf("got %d VTODOs, want 3:\n%s", len(todos), data)
+	}
+	if todos[0].Summary != "call the bank" || !todos[0].AllDay || todos[0].Due.Day() != 5 || todos[0].Priority != 1 {
+		t.Errorf("active task VTODO = %+v", todos[0])
+	}
+	if todos[0].UID != task.ID(lines[0])+"@taskmasterra" {
+		t.Errorf("active task UID = %q", todos[0].UID)
+	}
+	if todos[1].RRule != "FREQ=DAILY;INTERVAL=30" || todos[1].Description != "the ones on the balcony" {
+		t.Errorf("repeating task VTODO = %+v", todos[1])
+	}
+
+	if got := strings.Count(data, "BEGIN:VEVENT"); got != 2 {
+		t.Errorf("got %d VEVENTs, want 2 (dated tasks only):\n%s", got, data)
+	}
+	for _, want := range []string{
+		"DTSTART;VALUE=DATE:20211210",
+		"DTEND;VALUE=DATE:20211211",
+		"RRULE:FREQ=WEEKLY;INTERVAL=1",
+		"X-WR-CALNAME:Taskmasterra",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("ICS() missing %q:\n%s", want, data)
+		}
+	}
+	if strings.Contains(data, "finished") || strings.Contains(data, "someday") {
+		t.Errorf("ICS() exported a completed or undated task:\n%s", data)
+	}
+}
+
+func TestICSOptions(t *testing.T) {
+	tasks := task.Parse([]string{"- [ ] renew passport <2024-06-01 Sat>"})
+	now := time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC)
+
+	eventsOnly := ICS(tasks, now, ICSOptions{Events: true})
+	if strings.Contains(eventsOnly, "VTODO") || !strings.Contains(eventsOnly, "VEVENT") {
+		t.Errorf("events-only export = %s", eventsOnly)
+	}
+
+	todosOnly := ICS(tasks, now, ICSOptions{Todos: true})
+	if strings.Contains(todosOnly, "VEVENT") || !strings.Contains(todosOnly, "DUE;VALUE=DATE:20240601") {
+		t.Errorf("todos-only export = %s", todosOnly)
+	}
+}
+
+func TestICSUniqueUIDs(t *testing.T) {
+	lines := []string{
+		"- [ ] !! call mom",
+		"- [ ] !! call mom",
+	}
+	now := time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC)
+
+	todos, err := ical.Parse(ICS(task.Parse(lines), now, DefaultICSOptions()))
+	if err != nil {
+		t.Fatalf("ical.Parse() error = %v", err)
+	}
+	if len(todos) != 2 {
+		t.Fatalf("got %d VTODOs, want 2", len(todos))
+	}
+	id := task.ID(lines[0])
+	if todos[0].UID != id+"@taskmasterra" || todos[1].UID != id+"-2@taskmasterra" {
+		t.Errorf("UIDs = %q, %q, want the second one numbered", todos[0].UID, todos[1].UID)
+	}
+}
@@ -0,0 +1,188 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+// OrgEffortProperty keeps the effort of a task. Org's own Effort property is a
+// duration, so the Fibonacci effort gets a property of its own.
+const OrgEffortProperty = "EFFORT_POINTS"
+
+// OrgActiveTag marks active (!!) tasks
+const OrgActiveTag = "active"
+
+// Precompiled regex patterns for better performance
+var (
+	orgTagRegex      = regexp.MustCompile(`^[\w@#%]+$`)
+	orgScheduleRegex = regexp.MustCompile(`SCHEDULED:\s*<`)
+)
+
+// OrgOptions selects the history written by Org
+type OrgOptions struct {
+	Journal []journal.Entry // written as state changes to the LOGBOOK of their task
+	Archive []journal.Entry // written as DONE tasks under an "Archive" heading with the ARCHIVE tag
+}
+
+// Org returns the tasks as an org-mode outline: sections are level 1
+// headlines, tasks TODO, STARTED, WAITING or DONE headlines below them with
+// their [#A] priority and :tags:, and subtasks one level deeper. Task dates
+// become DEADLINE, or SCHEDULED when the todo file says so, and details the
+// headline body. Tags that org cannot express stay in the title.
+func Org(tasks []task.Task, opts OrgOptions) string {
+	worked := make(map[string][]journal.Entry)
+	for _, entry := range opts.Journal {
+		id := task.ID(entry.Line)
+		worked[id] = append(worked[id], entry)
+	}
+
+	var b strings.Builder
+	section := ""
+	for _, t := range tasks {
+		if t.Section != section {
+			section = t.Section
+			fmt.Fprintf(&b, "* %s\n", section)
+		}
+		level := 1
+		if section != "" {
+			level++
+		}
+		if t.Subtask {
+			level++
+		}
+		writeOrgTask(&b, level, t, "", nil)
+		writeOrgLogbook(&b, worked[t.ID])
+		writeOrgBody(&b, t)
+	}
+
+	if len(opts.Archive) > 0 {
+		b.WriteString("* Archive :ARCHIVE:\n")
+		for _, entry := range opts.Archive {
+			lines := append([]string{entry.Line}, entry.Details...)
+			for _, t := range task.Parse(lines) {
+				if t.Subtask {
+					writeOrgTask(&b, 3, t, "", nil)
+				} else {
+					closed := orgInactive(entry.Timestamp)
+					properties := []string{":ARCHIVE_TIME: " + strings.Trim(closed, "[]")}
+					if entry.Section != "" {
+						properties = append(properties, ":ARCHIVE_OLPATH: "+entry.Section)
+					}
+					writeOrgTask(&b, 2, t, closed, properties)
+				}
+				writeOrgBody(&b, t)
+			}
+		}
+	}
+	return b.String()
+}
+
+// writeOrgTask writes the headline, planning line and property drawer of a
+// task. closed is the inactive CLOSED timestamp of a completed task, if known,
+// and properties are ":NAME: value" lines added to the drawer.
+func writeOrgTask(b *strings.Builder, level int, t task.Task, closed string, properties []string) {
+	headline := []string{strings.Repeat("*", level), orgKeyword(t.Status)}
+	if letter := t.Priority.Letter(); letter != "" {
+		headline = append(headline, "[#"+letter+"]")
+	}
+
+	title := t.Title
+	var tags []string
+	for _, tag := range t.Tags {
+		if orgTagRegex.MatchString(tag) {
+			tags = append(tags, tag)
+			title = removeTag(title, tag)
+		}
+	}
+	if t.Active {
+		tags = append(tags, OrgActiveTag)
+	}
+	headline = append(headline, title)
+	if len(tags) > 0 {
+		headline = append(headline, ":"+strings.Join(tags, ":")+":")
+	}
+	b.WriteString(strings.Join(headline, " ") + "\n")
+
+	var planning []string
+	if t.Date != nil {
+		keyword := "DEADLINE"
+		if orgScheduleRegex.MatchString(strings.Join(append([]string{t.Line}, t.Details...), "\n")) {
+			keyword = "SCHEDULED"
+		}
+		planning = append(planning, keyword+": "+t.Date.String())
+	}
+	if closed != "" {
+		planning = append(planning, "CLOSED: "+closed)
+	}
+	if len(planning) > 0 {
+		b.WriteString(strings.Join(planning, " ") + "\n")
+	}
+
+	if t.Effort > 0 {
+		properties = append([]string{fmt.Sprintf(":%s: %d", OrgEffortProperty, t.Effort)}, properties...)
+	}
+	if len(properties) > 0 {
+		b.WriteString(":PROPERTIES:\n" + strings.Join(properties, "\n") + "\n:END:\n")
+	}
+}
+
+// writeOrgLogbook writes journal entries as a LOGBOOK of state changes, newest first
+func writeOrgLogbook(b *strings.Builder, entries []journal.Entry) {
+	if len(entries) == 0 {
+		return
+	}
+	sorted := append([]journal.Entry{}, entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.After(sorted[j].Timestamp)
+	})
+	b.WriteString(":LOGBOOK:\n")
+	for _, entry := range sorted {
+		status := ""
+		if info := task.ParseTaskInfo(strings.TrimLeft(entry.Line, " \t")); info != nil {
+			status = info.Status
+		}
+		fmt.Fprintf(b, "- State %-12s from %-12s %s\n", `"`+orgKeyword(status)+`"`, "", orgInactive(entry.Timestamp))
+	}
+	b.WriteString(":END:\n")
+}
+
+// writeOrgBody writes the details of a task that are more than a date
+func writeOrgBody(b *strings.Builder, t task.Task) {
+	for _, detail := range t.Details {
+		if task.StripDate(detail) == "" {
+			continue
+		}
+		b.WriteString("- " + detail + "\n")
+	}
+}
+
+// orgKeyword returns the TODO keyword for a status character
+func orgKeyword(status string) string {
+	switch strings.ToLower(status) {
+	case "w":
+		return "STARTED"
+	case "b":
+		return "WAITING"
+	case "x":
+		return "DONE"
+	default:
+		return "TODO"
+	}
+}
+
+// orgInactive returns an inactive org timestamp such as [2024-03-05 Tue 10:00]
+func orgInactive(t time.Time) string {
+	return "[" + t.Format("2006-01-02 Mon 15:04") + "]"
+}
+
+// removeTag removes a #tag from a title
+func removeTag(title, tag string) string {
+	pattern := regexp.MustCompile(`(^|[\s:(])#` + regexp.QuoteMeta(tag) + `\b`)
+	return strings.Join(strings.Fields(pattern.ReplaceAllString(title, "$1")), " ")
+}
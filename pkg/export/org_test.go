@@ -0,0 +1,94 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/importer"
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+func TestOrg(t *testing.T) {
+	lines := []string{
+		"- [ ] Loose task",
+		"## Work",
+		"- [ ] !! A2 Write #docs report <2024-03-09 Sat>",
+		"  outline first",
+		"  - [x] C1 gather numbers",
+		"- [b] Wait for #legal/review",
+		"- [w] B1 Water plants",
+		"  SCHEDULED: <2024-03-10 Sun .+1w>",
+	}
+	tasks := task.Parse(lines)
+	at := time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)
+	opts := OrgOptions{
+		Journal: []journal.Entry{{Timestamp: at, Line: "- [W] !! A2 Write #docs report <2024-03-09 Sat>"}},
+		Archive: []journal.Entry{{
+			Timestamp: at.Add(24 * time.Hour),
+			Line:      "- [x] D3 Renew passport #travel",
+			Details:   []string{"  - photo booth", "  - [x] fill form"},
+			Section:   "Travel",
+		}},
+	}
+
+	want := "* TODO Loose task\n" +
+		"* Work\n" +
+		"** TODO [#A] Write report :docs:active:\n" +
+		"DEADLINE: <2024-03-09 Sat>\n" +
+		":PROPERTIES:\n:EFFORT_POINTS: 2\n:END:\n" +
+		":LOGBOOK:\n- State \"STARTED\"    from              [2024-03-04 Mon 09:30]\n:END:\n" +
+		"- outline first\n" +
+		"*** DONE [#C] gather numbers\n" +
+		":PROPERTIES:\n:EFFORT_POINTS: 1\n:END:\n" +
+		"** WAITING Wait for #legal/review\n" +
+		"** STARTED [#B] Water plants\n" +
+		"SCHEDULED: <2024-03-10 Sun .+1w>\n" +
+		":PROPERTIES:\n:EFFORT_POINTS: 1\n:END:\n" +
+		"* Archive :ARCHIVE:\n" +
+		"** DONE [#D] Renew passport :travel:\n" +
+		"CLOSED: [2024-03-05 Tue 09:30]\n" +
+		":PROPERTIES:\n:EFFORT_POINTS: 3\n:ARCHIVE_TIME: 2024-03-05 Tue 09:30\n:ARCHIVE_OLPATH: Travel\n:END:\n" +
+		"- photo booth\n" +
+		"*** DONE fill form\n"
+
+	if got := Org(tasks, opts); got != want {
+		t.Errorf("Org() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestOrgRoundTrip(t *testing.T) {
+	lines := []string{
+		"## Work",
+		"- [ ] !! A2 Write #docs report <2024-03-09 Sat 14:00>",
+		"  outline first",
+		"  - [ ] gather numbers",
+		"- [w] B1 Water plants",
+		"  SCHEDULED: <2024-03-10 Sun .+1w>",
+	}
+	tasks := task.Parse(lines)
+
+	items := importer.Org(Org(tasks, OrgOptions{}), time.Local)
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2: %+v", len(items), items)
+	}
+	want := [][]string{
+		{"- [ ] !! A2 Write report #docs DEADLINE: <2024-03-09 Sat 14:00>", "  - outline first", "  - [ ] gather numbers"},
+		{"- [w] B1 Water plants SCHEDULED: <2024-03-10 Sun .+1w>"},
+	}
+	for i, item := range items {
+		got := item.Lines()
+		if len(got) != len(want[i]) {
+			t.Errorf("item %d Lines() = %q, want %q", i, got, want[i])
+			continue
+		}
+		for j := range got {
+			if got[j] != want[i][j] {
+				t.Errorf("item %d line %d = %q, want %q", i, j, got[j], want[i][j])
+			}
+		}
+		if item.Section != "Work" || importer.MatchKey(got[0]) != importer.MatchKey(lines[[]int{1, 4}[i]]) {
+			t.Errorf("item %d = %+v, want to match %q", i, item, lines[[]int{1, 4}[i]])
+		}
+	}
+}
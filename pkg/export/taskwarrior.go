@@ -0,0 +1,67 @@
+package export
+
+import (
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+	"github.com/robertarles/taskmasterra/v2/pkg/taskwarrior"
+)
+
+// Taskwarrior returns the tasks and subtasks as JSON for `task import`.
+// Priorities A and B become H, C becomes M and D becomes L; the section is the
+// project, #tags are also tags, details are annotations, active (!!) tasks are
+// started and blocked tasks get the "blocked" tag. The effort is kept in an
+// effort attribute. Descriptions keep their #tags, so the tasks are matched
+// by title when they are imported back. UUIDs are derived from task IDs, so
+// importing a later export updates the same Taskwarrior tasks. now is used
+// for the entry, start and end times, which the todo file does not record.
+func Taskwarrior(tasks []task.Task, now time.Time) (string, error) {
+	records := make([]taskwarrior.Task, 0, len(tasks))
+	for _, t := range tasks {
+		record := taskwarrior.Task{
+			UUID:        taskwarrior.UUID(t.ID),
+			Description: t.Title,
+			Status:      taskwarrior.StatusPending,
+			Entry:       taskwarrior.NewTime(now),
+			Priority:    taskwarriorPriority(t.Priority),
+			Project:     t.Section,
+			Tags:        append([]string{}, t.Tags...),
+			Effort:      t.Effort,
+		}
+		if task.IsCompleted(strings.TrimLeft(t.Line, " \t")) {
+			record.Status = taskwarrior.StatusCompleted
+			record.End = taskwarrior.NewTime(now)
+		} else if t.Active {
+			record.Start = taskwarrior.NewTime(now)
+		}
+		if strings.EqualFold(t.Status, "b") {
+			record.Tags = append(record.Tags, "blocked")
+		}
+		if t.Date != nil {
+			record.Due = taskwarrior.NewTime(t.Date.Time)
+			if r := t.Date.Repeater; r != nil {
+				record.Recur = taskwarrior.FormatRecur(r.Interval, r.Unit)
+			}
+		}
+		for _, detail := range t.Details {
+			record.Annotations = append(record.Annotations, taskwarrior.Annotation{Entry: taskwarrior.NewTime(now), Description: detail})
+		}
+		records = append(records, record)
+	}
+	return taskwarrior.Encode(records)
+}
+
+// taskwarriorPriority maps a task priority onto Taskwarrior's H, M and L
+func taskwarriorPriority(p task.Priority) string {
+	switch p {
+	case task.PriorityCritical, task.PriorityHigh:
+		return taskwarrior.PriorityHigh
+	case task.PriorityMedium:
+		return taskwarrior.PriorityMedium
+	case task.PriorityLow:
+		return taskwarrior.PriorityLow
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,58 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/importer"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+	"github.com/robertarles/taskmasterra/v2/pkg/taskwarrior"
+)
+
+func TestTaskwarrior(t *testing.T) {
+	lines := []string{
+		"## Work",
+		"- [ ] !! B3 Review #code PR <2024-03-09 Sat +2w>",
+		"  the big one",
+		"- [x] A1 Ship release",
+		"- [b] D2 Wait for #legal",
+	}
+	now := time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC)
+	tasks := task.Parse(lines)
+
+	data, err := Taskwarrior(tasks, now)
+	if err != nil {
+		t.Fatalf("Taskwarrior() error = %v", err)
+	}
+	records, err := taskwarrior.Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("taskwarrior.Parse() error = %v\n%s", err, data)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3:\n%s", len(records), data)
+	}
+
+	review := records[0]
+	if review.UUID != taskwarrior.UUID(tasks[0].ID) || review.Description != "Review #code PR" || review.Priority != "H" || review.Project != "Work" {
+		t.Errorf("review = %+v", review)
+	}
+	if review.Start == nil || review.Recur != "2weeks" || review.Effort != 3 || len(review.Annotations) != 1 || len(review.Tags) != 1 {
+		t.Errorf("review = %+v", review)
+	}
+	if ship := records[1]; ship.Status != taskwarrior.StatusCompleted || ship.End == nil || ship.Start != nil {
+		t.Errorf("ship = %+v", ship)
+	}
+	if wait := records[2]; wait.Priority != "L" || len(wait.Tags) != 2 || wait.Tags[1] != "blocked" {
+		t.Errorf("wait = %+v", wait)
+	}
+
+	// Reading the export back gives the same titles, sections and dates
+	for i, item := range importer.Taskwarrior(records, time.Local) {
+		if importer.MatchKey(item.Line()) != importer.MatchKey(tasks[i].Line) || item.Section != "Work" {
+			t.Errorf("item %d = %q, want to match %q", i, item.Line(), tasks[i].Line)
+		}
+		if (item.Date == nil) != (tasks[i].Date == nil) || item.Date != nil && item.Date.String() != tasks[i].Date.String() {
+			t.Errorf("item %d date = %v, want %v", i, item.Date, tasks[i].Date)
+		}
+	}
+}
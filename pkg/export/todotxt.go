@@ -0,0 +1,61 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+// TodoTxt returns the tasks and subtasks as todo.txt lines. Priorities A to D
+// become (A) to (D), or pri: on completed tasks, the section becomes a
+// +project (with spaces as _), #tags become @contexts, the date becomes due:
+// and its repeater rec:. The effort is kept in an effort: key. todo.txt has
+// no worked, blocked or active state, details or nesting, so those are not
+// exported.
+func TodoTxt(tasks []task.Task) string {
+	var b strings.Builder
+	for _, t := range tasks {
+		var parts []string
+		letter := t.Priority.Letter()
+		completed := task.IsCompleted(strings.TrimLeft(t.Line, " \t"))
+		if completed {
+			parts = append(parts, "x")
+		} else if letter != "" {
+			parts = append(parts, "("+letter+")")
+		}
+
+		parts = append(parts, task.ReplaceTags(t.Title, func(tag string) string { return "@" + tag }))
+		if t.Section != "" {
+			parts = append(parts, "+"+strings.Join(strings.Fields(t.Section), "_"))
+		}
+		if t.Date != nil {
+			parts = append(parts, "due:"+t.Date.Time.Format("2006-01-02"))
+			if rec := todoTxtRec(t.Date.Repeater); rec != "" {
+				parts = append(parts, "rec:"+rec)
+			}
+		}
+		if completed && letter != "" {
+			parts = append(parts, "pri:"+letter)
+		}
+		if t.Effort > 0 {
+			parts = append(parts, fmt.Sprintf("effort:%d", t.Effort))
+		}
+		b.WriteString(strings.Join(parts, " ") + "\n")
+	}
+	return b.String()
+}
+
+// todoTxtRec returns the rec: value for an org repeater. rec: repeats from
+// the completion date unless the value starts with +; hourly repeaters have
+// no equivalent.
+func todoTxtRec(r *task.Repeater) string {
+	if r == nil || r.Unit == 'h' {
+		return ""
+	}
+	rec := fmt.Sprintf("%d%c", r.Interval, r.Unit)
+	if r.Kind != ".+" {
+		rec = "+" + rec
+	}
+	return rec
+}
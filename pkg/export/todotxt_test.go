@@ -0,0 +1,51 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/importer"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+func TestTodoTxt(t *testing.T) {
+	lines := []string{
+		"## Home Stuff",
+		"- [ ] A2 Water plants #garden <2024-03-09 Sat .+1w>",
+		"- [w] Fix (the #bike) chain",
+		"  - [x] C1 buy oil",
+		"- [ ] D3 Stand-up <2024-03-05 Tue 09:30 +1d>",
+	}
+
+	want := "(A) Water plants @garden +Home_Stuff due:2024-03-09 rec:1w effort:2\n" +
+		"Fix (the @bike) chain +Home_Stuff\n" +
+		"x buy oil +Home_Stuff pri:C effort:1\n" +
+		"(D) Stand-up +Home_Stuff due:2024-03-05 rec:+1d effort:3\n"
+
+	if got := TodoTxt(task.Parse(lines)); got != want {
+		t.Errorf("TodoTxt() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTodoTxtRoundTrip(t *testing.T) {
+	lines := []string{
+		"## Work",
+		"- [ ] B3 Review #code PR <2024-03-09 Sat +2w>",
+		"- [x] A1 Ship release",
+	}
+	tasks := task.Parse(lines)
+
+	items := importer.TodoTxt(TodoTxt(tasks), time.Local)
+	if len(items) != len(tasks) {
+		t.Fatalf("got %d items, want %d", len(items), len(tasks))
+	}
+	for i, item := range items {
+		if got, want := item.Line(), strings.TrimSpace(tasks[i].Line); got != want {
+			t.Errorf("item %d Line() = %q, want %q", i, got, want)
+		}
+		if item.Section != "Work" {
+			t.Errorf("item %d section = %q, want Work", i, item.Section)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Package history provides querying and rendering of journal and archive entries.
+// It answers questions such as "what did I touch last Tuesday" without grepping
+// the journal files by hand.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+// Output formats supported by Render
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+)
+
+// Filter selects journal and archive entries.
+// Zero values match everything.
+type Filter struct {
+	From     time.Time // inclusive lower bound
+	Until    time.Time // exclusive upper bound
+	Tags     []string  // all tags must be present
+	Priority *task.Priority
+	Status   string // status letter, matched case-insensitively
+	Text     string // case-insensitive text in the task or its details
+}
+
+// Match reports whether an entry satisfies the filter
+func (f Filter) Match(entry journal.Entry) bool {
+	if !f.From.IsZero() && entry.Timestamp.Before(f.From) {
+		return false
+	}
+	if !f.Until.IsZero() && !entry.Timestamp.Before(f.Until) {
+		return false
+	}
+
+	info := parseEntryInfo(entry)
+
+	if f.Priority != nil && info.Priority != *f.Priority {
+		return false
+	}
+
+	if f.Status != "" && !strings.EqualFold(info.Status, f.Status) {
+		return false
+	}
+
+	if len(f.Tags) > 0 {
+		tags := task.ParseTags(entry.Line)
+		for _, want := range f.Tags {
+			if !containsFold(tags, strings.TrimPrefix(want, "#")) {
+				return false
+			}
+		}
+	}
+
+	if f.Text != "" {
+		text := strings.ToLower(f.Text)
+		found := strings.Contains(strings.ToLower(entry.Line), text)
+		for _, detail := range entry.Details {
+			if found {
+				break
+			}
+			found = strings.Contains(strings.ToLower(detail), text)
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Apply returns the entries matching the filter, newest first
+func Apply(entries []journal.Entry, filter Filter) []journal.Entry {
+	var matched []journal.Entry
+	for _, entry := range entries {
+		if filter.Match(entry) {
+			matched = append(matched, entry)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Timestamp.After(matched[j].Timestamp)
+	})
+
+	return matched
+}
+
+// ParseStatus converts a status letter or name (open, worked, blocked, done) to a status letter
+func ParseStatus(status string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "":
+		return "", nil
+	case "open", "todo":
+		return " ", nil
+	case "w", "worked":
+		return "w", nil
+	case "b", "blocked":
+		return "b", nil
+	case "x", "done", "completed":
+		return "x", nil
+	default:
+		return "", fmt.Errorf("unknown status '%s' (use open, worked, blocked or done)", status)
+	}
+}
+
+// entryJSON is the JSON representation of an entry
+type entryJSON struct {
+	ID        string   `json:"id"`
+	Timestamp string   `json:"timestamp"`
+	Source    string   `json:"source"`
+	Section   string   `json:"section,omitempty"`
+	Status    string   `json:"status"`
+	Priority  string   `json:"priority"`
+	Effort    int      `json:"effort,omitempty"`
+	Title     string   `json:"title"`
+	Tags      []string `json:"tags,omitempty"`
+	Details   []string `json:"details,omitempty"`
+}
+
+// Render formats entries as plain text, markdown or JSON.
+// Timestamps are shown in the location of the given timestamp format.
+func Render(entries []journal.Entry, format string, ts journal.TimestampFormat) (string, error) {
+	loc := ts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	switch format {
+	case FormatText, "":
+		return renderText(entries, loc), nil
+	case FormatMarkdown, "md":
+		return renderMarkdown(entries, loc), nil
+	case FormatJSON:
+		return renderJSON(entries, loc)
+	default:
+		return "", fmt.Errorf("unknown output format '%s' (use text, markdown or json)", format)
+	}
+}
+
+// renderText renders one line per entry followed by its details
+func renderText(entries []journal.Entry, loc *time.Location) string {
+	var output strings.Builder
+	for _, entry := range entries {
+		output.WriteString(fmt.Sprintf("%s  %-7s  %s  %s\n",
+			entry.Timestamp.In(loc).Format("2006-01-02 15:04"), entry.Source, task.ID(entry.Line), strings.TrimSpace(entry.Line)))
+		for _, detail := range entry.Details {
+			output.WriteString(fmt.Sprintf("    %s\n", strings.TrimSpace(detail)))
+		}
+	}
+	if len(entries) == 0 {
+		output.WriteString("No matching entries\n")
+	}
+	return output.String()
+}
+
+// renderMarkdown renders entries grouped under one header per day
+func renderMarkdown(entries []journal.Entry, loc *time.Location) string {
+	var output strings.Builder
+	output.WriteString("# Journal Log\n\n")
+
+	currentDay := ""
+	for _, entry := range entries {
+		day := entry.Timestamp.In(loc).Format("2006-01-02 Monday")
+		if day != currentDay {
+			if currentDay != "" {
+				output.WriteString("\n")
+			}
+			output.WriteString(fmt.Sprintf("## %s\n\n", day))
+			currentDay = day
+		}
+		output.WriteString(fmt.Sprintf("%s _(%s %s)_\n",
+			strings.TrimSpace(entry.Line), entry.Source, entry.Timestamp.In(loc).Format("15:04")))
+		for _, detail := range entry.Details {
+			output.WriteString(fmt.Sprintf("  %s\n", strings.TrimSpace(detail)))
+		}
+	}
+
+	if len(entries) == 0 {
+		output.WriteString("No matching entries\n")
+	}
+	return output.String()
+}
+
+// renderJSON renders entries as an indented JSON array
+func renderJSON(entries []journal.Entry, loc *time.Location) (string, error) {
+	items := make([]entryJSON, 0, len(entries))
+	for _, entry := range entries {
+		info := parseEntryInfo(entry)
+		details := make([]string, 0, len(entry.Details))
+		for _, detail := range entry.Details {
+			details = append(details, strings.TrimSpace(detail))
+		}
+		items = append(items, entryJSON{
+			ID:        task.ID(entry.Line),
+			Timestamp: entry.Timestamp.In(loc).Format(time.RFC3339),
+			Source:    entry.Source,
+			Section:   entry.Section,
+			Status:    info.Status,
+			Priority:  info.Priority.String(),
+			Effort:    info.Effort,
+			Title:     info.Title,
+			Tags:      task.ParseTags(entry.Line),
+			Details:   details,
+		})
+	}
+
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal entries to JSON: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// parseEntryInfo parses the task line of an entry, including indented subtasks
+func parseEntryInfo(entry journal.Entry) *task.TaskInfo {
+	info := task.ParseTaskInfo(strings.TrimLeft(entry.Line, " \t"))
+	if info == nil {
+		return &task.TaskInfo{Line: entry.Line, Title: strings.TrimSpace(entry.Line)}
+	}
+	return info
+}
+
+// containsFold reports whether values contains target, ignoring case
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package history
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+func testEntries() []journal.Entry {
+	return []journal.Entry{
+		{
+			Timestamp: time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC),
+			Line:      "- [W] A1 Write report #work",
+			Details:   []string{"  - drafted intro"},
+			Source:    journal.SourceJournal,
+		},
+		{
+			Timestamp: time.Date(2024, time.March, 5, 17, 0, 0, 0, time.UTC),
+			Line:      "- [x] C3 Buy milk #home",
+			Source:    journal.SourceArchive,
+		},
+		{
+			Timestamp: time.Date(2024, time.March, 6, 8, 0, 0, 0, time.UTC),
+			Line:      "  - [B] B2 Waiting on review #work #review",
+			Source:    journal.SourceJournal,
+		},
+	}
+}
+
+func TestApply(t *testing.T) {
+	critical := task.PriorityCritical
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"No filter, newest first", Filter{}, []string{"Waiting on review", "Buy milk", "Write report"}},
+		{"Date range", Filter{
+			From:  time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC),
+			Until: time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC),
+		}, []string{"Buy milk"}},
+		{"Tag", Filter{Tags: []string{"#work"}}, []string{"Waiting on review", "Write report"}},
+		{"Multiple tags", Filter{Tags: []string{"work", "review"}}, []string{"Waiting on review"}},
+		{"Priority", Filter{Priority: &critical}, []string{"Write report"}},
+		{"Status ignores case", Filter{Status: "b"}, []string{"Waiting on review"}},
+		{"Text in details", Filter{Text: "DRAFTED"}, []string{"Write report"}},
+		{"No match", Filter{Text: "nothing"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Apply(testEntries(), tt.filter)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apply() returned %d entries, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i].Line, want) {
+					t.Errorf("Entry %d = %q, want it to contain %q", i, got[i].Line, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"open", " ", false},
+		{"Worked", "w", false},
+		{"B", "b", false},
+		{"done", "x", false},
+		{"later", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseStatus(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseStatus(%q) = %q, %v, want %q, wantErr %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	entries := Apply(testEntries(), Filter{})
+	ts := journal.DefaultTimestampFormat()
+
+	text, err := Render(entries, FormatText, ts)
+	if err != nil {
+		t.Fatalf("Render(text) failed: %v", err)
+	}
+	wantLine := "2024-03-04 09:00  journal  " + task.ID("- [W] A1 Write report #work") + "  - [W] A1 Write report #work"
+	if !strings.Contains(text, wantLine) {
+		t.Errorf("Text output missing entry line:\n%s", text)
+	}
+	if !strings.Contains(text, "    - drafted intro") {
+		t.Errorf("Text output missing detail line:\n%s", text)
+	}
+
+	markdown, err := Render(entries, FormatMarkdown, ts)
+	if err != nil {
+		t.Fatalf("Render(markdown) failed: %v", err)
+	}
+	if !strings.Contains(markdown, "## 2024-03-05 Tuesday") {
+		t.Errorf("Markdown output missing day header:\n%s", markdown)
+	}
+
+	output, err := Render(entries, FormatJSON, ts)
+	if err != nil {
+		t.Fatalf("Render(json) failed: %v", err)
+	}
+	var decoded []entryJSON
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("JSON output does not parse: %v", err)
+	}
+	if len(decoded) != 3 || decoded[2].Priority != "Critical" || decoded[2].Tags[0] != "work" {
+		t.Errorf("Unexpected JSON output: %+v", decoded)
+	}
+	if decoded[0].Status != "B" || decoded[0].Title != "B2 Waiting on review #work #review" {
+		t.Errorf("Indented subtask not parsed: %+v", decoded[0])
+	}
+
+	if _, err := Render(entries, "xml", ts); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
@@ -0,0 +1,395 @@
+// Package ical reads and writes the subset of iCalendar (RFC 5545) needed to
+// exchange tasks as VTODO components and publish them as VEVENT components.
+package ical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProductID identifies taskmasterra as the producer of calendar data
+const ProductID = "-//taskmasterra//taskmasterra//EN"
+
+// dateTimeLayout is the UTC DATE-TIME form written by Encode
+const dateTimeLayout = "20060102T150405Z"
+
+// dateLayout is the DATE form used for whole-day values
+const dateLayout = "20060102"
+
+// flaggedProperty keeps the reminder flag, which iCalendar has no property for
+const flaggedProperty = "X-TASKMASTERRA-FLAGGED"
+
+// Todo is a VTODO component
+type Todo struct {
+	UID         string
+	Summary     string
+	Description string
+	Due         time.Time // zero for no due date; written as DTSTART when RRule is set
+	Priority    int       // 0 undefined, 1 highest to 9 lowest
+	Completed   bool
+	Flagged     bool
+	Categories  []string
+	AllDay      bool   // write Due as a DATE value
+	RRule       string // recurrence rule such as "FREQ=WEEKLY;INTERVAL=1"
+
+	// Extra holds the unfolded content lines of the properties and components
+	// (such as VALARM) that Todo does not model, so that a VTODO read by Parse
+	// is written back by Encode without losing what other clients set
+	Extra []string
+}
+
+// Event is a VEVENT component
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	Duration    time.Duration // zero for a whole day (AllDay) or an instant
+	AllDay      bool          // write Start as a DATE value
+	RRule       string
+	Categories  []string
+}
+
+// Calendar holds the components written by EncodeCalendar
+type Calendar struct {
+	Name   string // X-WR-CALNAME shown by subscribing calendar apps
+	Todos  []Todo
+	Events []Event
+}
+
+// Encode returns a VCALENDAR containing the todos. now is used for DTSTAMP.
+func Encode(todos []Todo, now time.Time) string {
+	return EncodeCalendar(Calendar{Todos: todos}, now)
+}
+
+// EncodeCalendar returns a VCALENDAR containing the calendar's todos and
+// events. now is used for DTSTAMP.
+func EncodeCalendar(cal Calendar, now time.Time) string {
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+ProductID)
+	if cal.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(cal.Name))
+	}
+	for _, todo := range cal.Todos {
+		writeLine(&b, "BEGIN:VTODO")
+		writeLine(&b, "UID:"+escapeText(todo.UID))
+		writeLine(&b, "DTSTAMP:"+now.UTC().Format(dateTimeLayout))
+		writeLine(&b, "SUMMARY:"+escapeText(todo.Summary))
+		if todo.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(todo.Description))
+		}
+		switch {
+		case todo.Due.IsZero():
+		case todo.RRule != "" && !hasProperty(todo.Extra, "DTSTART"):
+			// A recurring VTODO needs DTSTART to anchor its recurrence set,
+			// and DUE must be later than DTSTART, so the due date is the anchor
+			writeLine(&b, formatDateTime("DTSTART", todo.Due, todo.AllDay))
+		default:
+			writeLine(&b, formatDateTime("DUE", todo.Due, todo.AllDay))
+		}
+		if todo.RRule != "" {
+			writeLine(&b, "RRULE:"+todo.RRule)
+		}
+		if todo.Priority > 0 {
+			writeLine(&b, "PRIORITY:"+strconv.Itoa(todo.Priority))
+		}
+		writeCategories(&b, todo.Categories)
+		if todo.Flagged {
+			writeLine(&b, flaggedProperty+":TRUE")
+		}
+		if todo.Completed {
+			writeLine(&b, "STATUS:COMPLETED")
+		} else {
+			writeLine(&b, "STATUS:NEEDS-ACTION")
+		}
+		for _, line := range todo.Extra {
+			if name, _, _, _ := splitProperty(line); name == "COMPLETED" && !todo.Completed {
+				continue // the completion time of a reopened todo
+			}
+			writeLine(&b, line)
+		}
+		writeLine(&b, "END:VTODO")
+	}
+	for _, event := range cal.Events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+escapeText(event.UID))
+		writeLine(&b, "DTSTAMP:"+now.UTC().Format(dateTimeLayout))
+		writeLine(&b, formatDateTime("DTSTART", event.Start, event.AllDay))
+		switch {
+		case event.AllDay:
+			writeLine(&b, formatDateTime("DTEND", event.Start.AddDate(0, 0, 1), true))
+		case event.Duration > 0:
+			writeLine(&b, formatDateTime("DTEND", event.Start.Add(event.Duration), false))
+		}
+		if event.RRule != "" {
+			writeLine(&b, "RRULE:"+event.RRule)
+		}
+		writeLine(&b, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		writeCategories(&b, event.Categories)
+		writeLine(&b, "TRANSP:TRANSPARENT")
+		writeLine(&b, "END:VEVENT")
+	}
+	writeLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// formatDateTime returns a DATE or UTC DATE-TIME property
+func formatDateTime(name string, t time.Time, allDay bool) string {
+	if allDay {
+		return name + ";VALUE=DATE:" + t.Format(dateLayout)
+	}
+	return name + ":" + t.UTC().Format(dateTimeLayout)
+}
+
+// writeCategories writes a CATEGORIES property when there are categories
+func writeCategories(b *strings.Builder, categories []string) {
+	if len(categories) == 0 {
+		return
+	}
+	escaped := make([]string, len(categories))
+	for i, category := range categories {
+		escaped[i] = escapeText(category)
+	}
+	writeLine(b, "CATEGORIES:"+strings.Join(escaped, ","))
+}
+
+// Parse returns the VTODO components in calendar data. Properties and
+// components of a VTODO that are not part of Todo are kept in Extra, except
+// DTSTAMP, which Encode writes anew.
+func Parse(data string) ([]Todo, error) {
+	var todos []Todo
+	var current *Todo
+	depth := 0 // nesting of components such as VALARM inside the current VTODO
+
+	for _, line := range unfold(data) {
+		if line == "" {
+			continue
+		}
+		name, params, value, ok := splitProperty(line)
+		if !ok {
+			return nil, fmt.Errorf("invalid content line %q", line)
+		}
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VTODO") && current == nil:
+			current, depth = &Todo{}, 0
+		case current != nil && name == "BEGIN":
+			depth++
+			current.Extra = append(current.Extra, line)
+		case current != nil && name == "END" && depth > 0:
+			depth--
+			current.Extra = append(current.Extra, line)
+		case name == "END" && strings.EqualFold(value, "VTODO"):
+			if current != nil {
+				if err := dueFromStart(current); err != nil {
+					return nil, err
+				}
+				todos = append(todos, *current)
+			}
+			current = nil
+		case current == nil:
+			continue
+		case depth > 0:
+			current.Extra = append(current.Extra, line)
+		case name == "UID":
+			current.UID = unescapeText(value)
+		case name == "SUMMARY":
+			current.Summary = unescapeText(value)
+		case name == "DESCRIPTION":
+			current.Description = unescapeText(value)
+		case name == "DUE":
+			due, err := parseDateTime(value, params)
+			if err != nil {
+				return nil, fmt.Errorf("invalid DUE %q: %w", value, err)
+			}
+			current.Due = due
+			current.AllDay = len(value) == len(dateLayout)
+		case name == "RRULE":
+			current.RRule = value
+		case name == "PRIORITY":
+			priority, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid PRIORITY %q: %w", value, err)
+			}
+			current.Priority = priority
+		case name == "STATUS":
+			current.Completed = strings.EqualFold(value, "COMPLETED")
+		case name == "COMPLETED":
+			current.Completed = true
+			current.Extra = append(current.Extra, line)
+		case name == flaggedProperty:
+			current.Flagged = strings.EqualFold(value, "TRUE")
+		case name == "CATEGORIES":
+			for _, category := range splitUnescaped(value, ',') {
+				current.Categories = append(current.Categories, unescapeText(category))
+			}
+		case name == "DTSTAMP":
+			// written anew by Encode
+		default:
+			current.Extra = append(current.Extra, line)
+		}
+	}
+
+	return todos, nil
+}
+
+// dueFromStart reads the due date of a recurring todo without DUE from its
+// DTSTART, the form Encode writes it in
+func dueFromStart(todo *Todo) error {
+	if !todo.Due.IsZero() || todo.RRule == "" {
+		return nil
+	}
+	for i, line := range todo.Extra {
+		name, params, value, _ := splitProperty(line)
+		if name != "DTSTART" {
+			continue
+		}
+		start, err := parseDateTime(value, params)
+		if err != nil {
+			return fmt.Errorf("invalid DTSTART %q: %w", value, err)
+		}
+		todo.Due, todo.AllDay = start, len(value) == len(dateLayout)
+		todo.Extra = append(todo.Extra[:i:i], todo.Extra[i+1:]...)
+		return nil
+	}
+	return nil
+}
+
+// hasProperty reports whether content lines contain a property named name
+func hasProperty(lines []string, name string) bool {
+	for _, line := range lines {
+		if n, _, _, _ := splitProperty(line); n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// writeLine writes a content line folded so that no line is longer than 75
+// octets, as RFC 5545 requires. Continuation lines start with a space, which
+// counts towards the limit.
+func writeLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		// Do not split a UTF-8 sequence
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	b.WriteString(line + "\r\n")
+}
+
+// unfold joins folded content lines
+func unfold(data string) []string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	var lines []string
+	for _, line := range strings.Split(data, "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitProperty splits a content line into its upper-cased name, parameters and value
+func splitProperty(line string) (string, map[string]string, string, bool) {
+	colon := -1
+	quoted := false
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	params := make(map[string]string)
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:], true
+}
+
+// parseDateTime parses a DATE or DATE-TIME value, honouring a TZID parameter
+func parseDateTime(value string, params map[string]string) (time.Time, error) {
+	loc := time.Local
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		return time.Parse(dateTimeLayout, value)
+	case len(value) == len(dateLayout):
+		return time.ParseInLocation(dateLayout, value, loc)
+	default:
+		return time.ParseInLocation("20060102T150405", value, loc)
+	}
+}
+
+// escapeText escapes a TEXT value
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, ";", `\;`)
+	s = strings.ReplaceAll(s, ",", `\,`)
+	s = strings.ReplaceAll(s, "\r\n", `\n`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+// unescapeText reverses escapeText
+func unescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// splitUnescaped splits s at sep characters that are not escaped with a backslash
+func splitUnescaped(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -0,0 +1,169 @@
+package ical
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeParseRoundTrip(t *testing.T) {
+	now := time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC)
+	todos := []Todo{
+		{
+			UID:         "abc12345@taskmasterra",
+			Summary:     "Call the bank; ask about fees, then \\ report",
+			Description: "Priority: High\nsecond line",
+			Due:         time.Date(2024, 3, 5, 16, 0, 0, 0, time.UTC),
+			Priority:    1,
+			Categories:  []string{"work", "a,b"},
+		},
+		{UID: "done@taskmasterra", Summary: "Finished", Completed: true},
+	}
+
+	data := Encode(todos, now)
+	for _, want := range []string{"BEGIN:VCALENDAR\r\n", "PRODID:" + ProductID, "DTSTAMP:20240305T090000Z", "DUE:20240305T160000Z", "STATUS:COMPLETED", `SUMMARY:Call the bank\; ask about fees\, then \\ report`} {
+		if !strings.Contains(data, want) {
+			t.Errorf("Encode() missing %q:\n%s", want, data)
+		}
+	}
+
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(parsed, todos) {
+		t.Errorf("Parse(Encode()) = %+v, want %+v", parsed, todos)
+	}
+}
+
+func TestEncodeFoldsLongLines(t *testing.T) {
+	summary := strings.Repeat("é", 60)
+	data := Encode([]Todo{{UID: "x", Summary: summary}}, time.Now())
+
+	for _, line := range strings.Split(data, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+
+	parsed, err := Parse(data)
+	if err != nil || len(parsed) != 1 || parsed[0].Summary != summary {
+		t.Errorf("Parse() = %+v, %v, want folded summary restored", parsed, err)
+	}
+}
+
+func TestWriteLineFoldsAt75Octets(t *testing.T) {
+	for _, length := range []int{74, 75, 76, 149, 150, 300} {
+		line := strings.Repeat("x", length)
+		var b strings.Builder
+		writeLine(&b, line)
+
+		folded := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+		for i, part := range folded {
+			if len(part) > 75 {
+				t.Errorf("length %d: line %d has %d octets: %q", length, i, len(part), part)
+			}
+			if i > 0 && !strings.HasPrefix(part, " ") {
+				t.Errorf("length %d: continuation line %d does not start with a space", length, i)
+			}
+		}
+		if got := unfold(b.String())[0]; got != line {
+			t.Errorf("length %d: unfolded line = %q, want the original", length, got)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	data := "BEGIN:VCALENDAR\n" +
+		"BEGIN:VEVENT\nUID:event\nSUMMARY:Not a todo\nEND:VEVENT\n" +
+		"BEGIN:VTODO\nUID:tz\nSUMMARY:With\n  folding\nDUE;TZID=America/New_York:20240305T090000\nCOMPLETED:20240306T100000Z\nEND:VTODO\n" +
+		"BEGIN:VTODO\nUID:date\nDUE;VALUE=DATE:20240307\nPRIORITY:5\nEND:VTODO\n" +
+		"END:VCALENDAR\n"
+
+	todos, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(todos) != 2 {
+		t.Fatalf("Parse() returned %d todos, want 2", len(todos))
+	}
+
+	newYork, _ := time.LoadLocation("America/New_York")
+	if todos[0].Summary != "With folding" || !todos[0].Due.Equal(time.Date(2024, 3, 5, 9, 0, 0, 0, newYork)) || !todos[0].Completed {
+		t.Errorf("todos[0] = %+v", todos[0])
+	}
+	if todos[1].Priority != 5 || todos[1].Due.Day() != 7 {
+		t.Errorf("todos[1] = %+v", todos[1])
+	}
+
+	if _, err := Parse("BEGIN:VTODO\nno colon here\nEND:VTODO"); err == nil {
+		t.Error("Parse() should reject lines without a value")
+	}
+}
+
+func TestParseKeepsUnknownProperties(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:x\r\nSUMMARY:Water plants\r\n" +
+		"DTSTAMP:20240301T000000Z\r\nX-APPLE-SORT-ORDER:42\r\nCOMPLETED:20240306T100000Z\r\n" +
+		"BEGIN:VALARM\r\nACTION:DISPLAY\r\nSUMMARY:Alarm\r\nTRIGGER:-PT15M\r\nEND:VALARM\r\n" +
+		"END:VTODO\r\nEND:VCALENDAR\r\n"
+
+	todos, err := Parse(data)
+	if err != nil || len(todos) != 1 {
+		t.Fatalf("Parse() = %v, %v", todos, err)
+	}
+	want := []string{"X-APPLE-SORT-ORDER:42", "COMPLETED:20240306T100000Z", "BEGIN:VALARM", "ACTION:DISPLAY", "SUMMARY:Alarm", "TRIGGER:-PT15M", "END:VALARM"}
+	if todos[0].Summary != "Water plants" || !reflect.DeepEqual(todos[0].Extra, want) {
+		t.Errorf("Parse() = %+v, want Extra %q", todos[0], want)
+	}
+
+	encoded := Encode(todos, time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC))
+	if !strings.Contains(encoded, "X-APPLE-SORT-ORDER:42\r\nCOMPLETED:20240306T100000Z\r\nBEGIN:VALARM\r\n") || strings.Count(encoded, "DTSTAMP") != 1 {
+		t.Errorf("Encode() did not write the kept properties back:\n%s", encoded)
+	}
+
+	// A reopened todo drops its completion time
+	todos[0].Completed = false
+	if encoded := Encode(todos, time.Now()); strings.Contains(encoded, "COMPLETED:") {
+		t.Errorf("Encode() kept COMPLETED for an open todo:\n%s", encoded)
+	}
+}
+
+func TestEncodeCalendarEvents(t *testing.T) {
+	now := time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC)
+	cal := Calendar{
+		Name: "Tasks",
+		Todos: []Todo{
+			{UID: "t@x", Summary: "Repeat", Due: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), AllDay: true, RRule: "FREQ=WEEKLY;INTERVAL=2"},
+		},
+		Events: []Event{
+			{UID: "e@x", Summary: "Meeting", Start: time.Date(2024, 3, 5, 14, 0, 0, 0, time.UTC), Duration: time.Hour},
+			{UID: "d@x", Summary: "Day", Start: time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC), AllDay: true},
+		},
+	}
+
+	data := EncodeCalendar(cal, now)
+	for _, want := range []string{
+		"X-WR-CALNAME:Tasks",
+		"DTSTART;VALUE=DATE:20240304\r\nRRULE:FREQ=WEEKLY;INTERVAL=2",
+		"BEGIN:VEVENT\r\nUID:e@x",
+		"DTSTART:20240305T140000Z\r\nDTEND:20240305T150000Z",
+		"DTSTART;VALUE=DATE:20240306\r\nDTEND;VALUE=DATE:20240307",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("EncodeCalendar() missing %q:\n%s", want, data)
+		}
+	}
+
+	todos, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if strings.Contains(data, "DUE") {
+		t.Errorf("EncodeCalendar() wrote DUE for a recurring todo anchored by DTSTART:\n%s", data)
+	}
+	if len(todos) != 1 || !todos[0].AllDay || todos[0].RRule != "FREQ=WEEKLY;INTERVAL=2" ||
+		!todos[0].Due.Equal(cal.Todos[0].Due) || len(todos[0].Extra) != 0 {
+		t.Errorf("Parse() = %+v, want the recurring all-day todo only", todos)
+	}
+}
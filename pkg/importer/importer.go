@@ -0,0 +1,229 @@
+// Package importer merges tasks read from other tools into a todo file, its journal and its archive.
+package importer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+	"github.com/robertarles/taskmasterra/v2/pkg/utils"
+)
+
+// Item is a task read from another tool
+type Item struct {
+	Title     string // may contain #tags
+	Status    string // status character: " ", "w", "b" or "x"
+	Active    bool
+	Priority  task.Priority
+	Effort    int
+	Section   string
+	Tags      []string // added as #tags unless the title already has them
+	Date      *task.Date
+	DateKind  string // "SCHEDULED" or "DEADLINE" to write the date as an org planning keyword
+	Details   []string
+	Subtasks  []Item
+	Completed time.Time   // completion time of a completed item, zero when unknown
+	Worked    []time.Time // times the item was worked on, written to the journal
+}
+
+// IsCompleted reports whether the item is done
+func (i Item) IsCompleted() bool {
+	return strings.EqualFold(i.Status, "x")
+}
+
+// Line returns the item as a markdown task line. The priority/effort code
+// needs both parts, so an effort of 1 is written for items with a priority
+// but no effort.
+func (i Item) Line() string {
+	status := i.Status
+	if status == "" {
+		status = " "
+	}
+	parts := []string{"- [" + status + "]"}
+	if i.Active {
+		parts = append(parts, "!!")
+	}
+	if code := i.Priority.Code(i.Effort); code != "" {
+		parts = append(parts, code)
+	}
+	parts = append(parts, strings.TrimSpace(i.Title))
+
+	existing := make(map[string]bool)
+	for _, tag := range task.ParseTags(i.Title) {
+		existing[strings.ToLower(tag)] = true
+	}
+	for _, tag := range i.Tags {
+		tag = strings.TrimPrefix(tag, "#")
+		if tag != "" && !existing[strings.ToLower(tag)] {
+			existing[strings.ToLower(tag)] = true
+			parts = append(parts, "#"+tag)
+		}
+	}
+	if i.Date != nil && i.DateKind != "" {
+		parts = append(parts, i.DateKind+": "+i.Date.String())
+	} else if i.Date != nil {
+		parts = append(parts, i.Date.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// Lines returns the task line followed by the item's details and subtasks
+func (i Item) Lines() []string {
+	lines := []string{i.Line()}
+	for _, detail := range i.Details {
+		lines = append(lines, "  - "+detail)
+	}
+	for _, subtask := range i.Subtasks {
+		for _, line := range subtask.Lines() {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return lines
+}
+
+// Result counts what Import did with the items
+type Result struct {
+	Added     int // open items added to the todo file
+	Completed int // open tasks in the todo file marked [X] because their item is done
+	Archived  int // completed items written to the archive
+	Journaled int // journal entries written for the Worked times of items
+	Skipped   int // items already in the todo file or archive
+}
+
+// Import merges items into the todo file at filePath. Items are matched to
+// existing tasks by title, ignoring case, status, priority/effort code and
+// dates, so importing the same items again changes nothing.
+//
+// Open items missing from the todo file are added to the end of their
+// section. Completed items whose task is open in the todo file mark it [X],
+// so the next recordkeep journals and archives it. Other completed items are
+// written to the archive stamped with their completion time, or now when
+// they have none; a repeating task can be archived once per completion time.
+// The Worked times of every item are written to the journal, skipping times
+// already journaled for the task.
+func Import(filePath string, items []Item, opts task.ProcessOptions) (*Result, error) {
+	content, err := utils.ReadFileContent(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file '%s': %w", filePath, err)
+	}
+	lines := strings.Split(content, "\n")
+
+	jm := journal.NewManager(filePath)
+	jm.Timestamp = opts.Timestamp
+	if opts.JournalLayout != "" {
+		jm.Layout = opts.JournalLayout
+	}
+	archived, err := jm.ReadArchive(opts.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive for '%s': %w", filePath, err)
+	}
+	journaled, err := jm.ReadJournal(opts.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal for '%s': %w", filePath, err)
+	}
+
+	open := make(map[string]string) // match key -> task ID of open tasks
+	inTodo := make(map[string]bool)
+	known := make(map[string]bool)
+	for _, t := range task.Parse(lines) {
+		key := MatchKey(t.Line)
+		inTodo[key] = true
+		known[key] = true
+		if !task.IsCompleted(strings.TrimLeft(t.Line, " \t")) {
+			open[key] = t.ID
+		}
+	}
+	// archived and journaled task keys, with and without their timestamp
+	stamped := make(map[string]bool)
+	for _, entry := range archived {
+		key := MatchKey(entry.Line)
+		known[key] = true
+		stamped[key+"@"+opts.Timestamp.Format(entry.Timestamp)] = true
+	}
+	worked := make(map[string]bool)
+	for _, entry := range journaled {
+		worked[MatchKey(entry.Line)+"@"+opts.Timestamp.Format(entry.Timestamp)] = true
+	}
+
+	result := &Result{}
+	complete := make(map[string]bool)
+	var archiveEntries, journalEntries []string
+	now := time.Now()
+	for _, item := range items {
+		key := MatchKey(item.Line())
+		for _, at := range item.Worked {
+			timestamp := opts.Timestamp.Format(at)
+			if !worked[key+"@"+timestamp] {
+				worked[key+"@"+timestamp] = true
+				lines := item.Lines()
+				journalEntries = append(journalEntries, timestamp+" "+lines[0])
+				journalEntries = append(journalEntries, lines[1:]...)
+				result.Journaled++
+			}
+		}
+
+		switch {
+		case item.IsCompleted() && open[key] != "":
+			if !complete[open[key]] {
+				complete[open[key]] = true
+				result.Completed++
+			}
+		case inTodo[key]:
+			result.Skipped++
+		case item.IsCompleted() && !item.Completed.IsZero() && !stamped[key+"@"+opts.Timestamp.Format(item.Completed)]:
+			archiveEntries = append(archiveEntries, archiveEntry(item, opts.Timestamp.Format(item.Completed))...)
+			stamped[key+"@"+opts.Timestamp.Format(item.Completed)] = true
+			known[key] = true
+			result.Archived++
+		case known[key]:
+			result.Skipped++
+		case item.IsCompleted():
+			archiveEntries = append(archiveEntries, archiveEntry(item, opts.Timestamp.Format(now))...)
+			known[key] = true
+			result.Archived++
+		default:
+			lines = task.InsertIntoSection(lines, item.Section, item.Lines())
+			inTodo[key] = true
+			known[key] = true
+			result.Added++
+		}
+	}
+
+	if result.Added > 0 || result.Completed > 0 {
+		lines, _ = task.CompleteByID(lines, complete)
+		if err := utils.WriteFileContent(filePath, strings.Join(lines, "\n")); err != nil {
+			return nil, fmt.Errorf("failed to update original file '%s': %w", filePath, err)
+		}
+	}
+	if err := jm.WriteToJournal(journalEntries); err != nil {
+		return nil, fmt.Errorf("failed to write journal entries for file '%s': %w", filePath, err)
+	}
+	if err := jm.WriteToArchive(archiveEntries); err != nil {
+		return nil, fmt.Errorf("failed to write archive entries for file '%s': %w", filePath, err)
+	}
+
+	return result, nil
+}
+
+// archiveEntry returns the archive lines for a completed item, written the
+// way recordkeep archives a task: the task line with its section, then its
+// details and subtasks with the same timestamp
+func archiveEntry(item Item, timestamp string) []string {
+	lines := item.Lines()
+	entry := []string{fmt.Sprintf("%s %s%s", timestamp, lines[0], journal.SectionComment(item.Section))}
+	for _, line := range lines[1:] {
+		entry = append(entry, timestamp+" "+line)
+	}
+	return entry
+}
+
+// MatchKey returns the key used to match a task line to an imported item:
+// its lowercase title without status, active marker, priority/effort code,
+// dates, #tags or repeated whitespace. Tags are left out because formats such
+// as org-mode keep them apart from the title.
+func MatchKey(line string) string {
+	title := task.ReplaceTags(task.CleanTitle(line), func(string) string { return "" })
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
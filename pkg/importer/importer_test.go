@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+func TestItemLine(t *testing.T) {
+	due := &task.Date{Time: time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC), Repeater: &task.Repeater{Kind: "+", Interval: 1, Unit: 'w'}}
+
+	tests := []struct {
+		name string
+		item Item
+		want string
+	}{
+		{"plain", Item{Title: "Call mom"}, "- [ ] Call mom"},
+		{"priority without effort", Item{Title: "Pay rent", Priority: task.PriorityHigh}, "- [ ] B1 Pay rent"},
+		{"completed with effort", Item{Title: "Ship", Status: "x", Priority: task.PriorityCritical, Effort: 3}, "- [x] A3 Ship"},
+		{"active", Item{Title: "Focus", Active: true}, "- [ ] !! Focus"},
+		{"tags and date", Item{Title: "Water plants #home", Tags: []string{"home", "#garden"}, Date: due}, "- [ ] Water plants #home #garden <2024-03-09 Sat +1w>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.item.Line(); got != tt.want {
+				t.Errorf("Line() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "taskmasterra-import-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	todoPath := filepath.Join(tempDir, "todo.md")
+	content := "## Work\n- [ ] A2 Write report\n- [ ] B1 Review PR\n"
+	if err := os.WriteFile(todoPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write todo file: %v", err)
+	}
+
+	opts := task.DefaultProcessOptions()
+	completed := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	items := []Item{
+		{Title: "write report", Priority: task.PriorityCritical}, // already present
+		{Title: "Review PR", Status: "x"},                        // completed in the other tool
+		{Title: "Book flights", Section: "Travel", Details: []string{"window seat"}},
+		{Title: "Plan sprint", Section: "Work", Priority: task.PriorityMedium, Effort: 2},
+		{Title: "Renew passport", Status: "x", Section: "Travel", Completed: completed, Details: []string{"photo booth"}},
+	}
+
+	result, err := Import(todoPath, items, opts)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if want := (Result{Added: 2, Completed: 1, Archived: 1, Skipped: 1}); *result != want {
+		t.Errorf("Import() = %+v, want %+v", *result, want)
+	}
+
+	data, _ := os.ReadFile(todoPath)
+	want := "## Work\n- [ ] A2 Write report\n- [X] B1 Review PR\n- [ ] C2 Plan sprint\n\n## Travel\n\n- [ ] Book flights\n  - window seat\n"
+	if string(data) != want {
+		t.Errorf("todo file =\n%s\nwant\n%s", data, want)
+	}
+
+	entries, err := journal.NewManager(todoPath).ReadArchive(opts.Timestamp)
+	if err != nil {
+		t.Fatalf("ReadArchive() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Line != "- [x] Renew passport" || entries[0].Section != "Travel" || !entries[0].Timestamp.Equal(completed) {
+		t.Fatalf("archive entries = %+v", entries)
+	}
+	if len(entries[0].Details) != 1 || strings.TrimSpace(entries[0].Details[0]) != "- photo booth" {
+		t.Errorf("archive details = %q", entries[0].Details)
+	}
+
+	// Importing the same items again changes nothing
+	result, err = Import(todoPath, items, opts)
+	if err != nil {
+		t.Fatalf("second Import() error = %v", err)
+	}
+	if want := (Result{Skipped: len(items)}); *result != want {
+		t.Errorf("second Import() = %+v, want %+v", *result, want)
+	}
+	if again, _ := os.ReadFile(todoPath); string(again) != want {
+		t.Errorf("todo file changed by second import:\n%s", again)
+	}
+}
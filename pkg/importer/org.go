@@ -0,0 +1,232 @@
+package importer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+// Precompiled regex patterns for better performance
+var (
+	orgHeadlineRegex = regexp.MustCompile(`^(\*+)\s+(.*?)\s*$`)
+	orgPriorityRegex = regexp.MustCompile(`^\[#([A-Z])\]\s*`)
+	orgTagsRegex     = regexp.MustCompile(`\s+:([\w@#%:]+):$`)
+	orgPlanningRegex = regexp.MustCompile(`(SCHEDULED|DEADLINE):\s*(<[^>]+>)`)
+	orgClosedRegex   = regexp.MustCompile(`CLOSED:\s*\[([^\]]+)\]`)
+	orgPropertyRegex = regexp.MustCompile(`^:([\w-]+):\s*(.*)$`)
+	orgInactiveRegex = regexp.MustCompile(`\[(\d{4}-\d{2}-\d{2})(?: [^\d\s\]]+)?(?: (\d{1,2}:\d{2}))?\]`)
+	orgStateRegex    = regexp.MustCompile(`^- State "[^"]*".*?(\[[^\]]+\])`)
+	orgClockRegex    = regexp.MustCompile(`^CLOCK:\s*(\[[^\]]+\])`)
+)
+
+// orgStatuses maps org TODO keywords to status characters; NEXT is also active
+var orgStatuses = map[string]string{
+	"TODO":        " ",
+	"NEXT":        " ",
+	"STARTED":     "w",
+	"DOING":       "w",
+	"IN-PROGRESS": "w",
+	"WAITING":     "b",
+	"HOLD":        "b",
+	"BLOCKED":     "b",
+	"DONE":        "x",
+	"CANCELLED":   "",
+	"CANCELED":    "",
+}
+
+// Org reads the TODO headlines of an org-mode file. Headlines without a TODO
+// keyword are sections, and TODO headlines below a task are its subtasks.
+// Priorities [#A] to [#D] map to A to D, :tags: become #tags (the "active" tag
+// and the NEXT keyword mark active tasks), SCHEDULED and DEADLINE give the
+// date, the EFFORT_POINTS property the effort, and body text the details.
+// Completed tasks are done at their CLOSED or ARCHIVE_TIME, and the times in
+// LOGBOOK state changes and clocks are journaled. Archived subtrees take their
+// section from ARCHIVE_OLPATH. Cancelled tasks are skipped. Times are
+// interpreted in loc.
+func Org(content string, loc *time.Location) []Item {
+	var items []Item
+	var current, target *Item
+	taskLevel := 0
+	section := ""
+	drawer := ""
+
+	flush := func() {
+		if current != nil && current.Status != "" {
+			items = append(items, *current)
+		}
+		current, target, taskLevel = nil, nil, 0
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if matches := orgHeadlineRegex.FindStringSubmatch(line); matches != nil {
+			drawer = ""
+			level := len(matches[1])
+			item, isTask := parseOrgHeadline(matches[2], loc)
+			switch {
+			case isTask && current != nil && level > taskLevel:
+				if item.Status != "" {
+					current.Subtasks = append(current.Subtasks, item)
+					target = &current.Subtasks[len(current.Subtasks)-1]
+				} else {
+					target = nil
+				}
+			case isTask:
+				flush()
+				item.Section = section
+				current, target, taskLevel = &item, &item, level
+			case current != nil && level > taskLevel:
+				// a plain headline inside a task is part of its body
+				if target != nil {
+					target.Details = append(target.Details, item.Title)
+				}
+			default:
+				flush()
+				if !containsTag(item.Tags, "ARCHIVE") {
+					section = item.Title
+				}
+			}
+			continue
+		}
+		if target == nil || trimmed == "" || strings.HasPrefix(trimmed, "#+") {
+			continue
+		}
+
+		switch {
+		case drawer != "" && strings.EqualFold(trimmed, ":END:"):
+			drawer = ""
+		case drawer == "" && strings.HasPrefix(trimmed, ":") && strings.HasSuffix(trimmed, ":") && len(trimmed) > 2:
+			drawer = strings.ToUpper(strings.Trim(trimmed, ":"))
+		case drawer == "PROPERTIES":
+			parseOrgProperty(target, trimmed, loc)
+		case drawer == "LOGBOOK":
+			if matches := orgStateRegex.FindStringSubmatch(trimmed); matches != nil {
+				appendOrgTime(&target.Worked, matches[1], loc)
+			} else if matches := orgClockRegex.FindStringSubmatch(trimmed); matches != nil {
+				appendOrgTime(&target.Worked, matches[1], loc)
+			}
+		case drawer != "":
+			// other drawers are not imported
+		case orgPlanningRegex.MatchString(trimmed) || orgClosedRegex.MatchString(trimmed):
+			parseOrgPlanning(target, trimmed, loc)
+		default:
+			target.Details = append(target.Details, strings.TrimPrefix(trimmed, "- "))
+		}
+	}
+	flush()
+	return items
+}
+
+// parseOrgHeadline parses the text of a headline after its stars. It reports
+// whether the headline has a TODO keyword; cancelled tasks get an empty status.
+func parseOrgHeadline(text string, loc *time.Location) (Item, bool) {
+	item := Item{}
+	if matches := orgTagsRegex.FindStringSubmatch(text); matches != nil {
+		text = text[:len(text)-len(matches[0])]
+		for _, tag := range strings.Split(matches[1], ":") {
+			switch {
+			case tag == "":
+			case strings.EqualFold(tag, "active"):
+				item.Active = true
+			default:
+				item.Tags = append(item.Tags, tag)
+			}
+		}
+	}
+
+	keyword, rest, _ := strings.Cut(text, " ")
+	status, isTask := orgStatuses[keyword]
+	if !isTask {
+		item.Title = strings.TrimSpace(text)
+		return item, false
+	}
+	item.Status = status
+	item.Active = item.Active || keyword == "NEXT"
+
+	rest = strings.TrimSpace(rest)
+	if matches := orgPriorityRegex.FindStringSubmatch(rest); matches != nil {
+		item.Priority = task.PriorityForLetter(matches[1])
+		rest = rest[len(matches[0]):]
+	}
+	item.Title = strings.TrimSpace(rest)
+	if date, ok := task.ParseDate(item.Title, loc); ok {
+		item.Date = date
+		item.Title = task.StripDate(item.Title)
+	}
+	return item, true
+}
+
+// parseOrgPlanning reads SCHEDULED, DEADLINE and CLOSED from a planning line
+func parseOrgPlanning(item *Item, line string, loc *time.Location) {
+	for _, matches := range orgPlanningRegex.FindAllStringSubmatch(line, -1) {
+		if item.Date != nil && item.DateKind == "DEADLINE" {
+			continue // a deadline wins over a scheduled date
+		}
+		if date, ok := task.ParseDate(matches[2], loc); ok {
+			item.Date, item.DateKind = date, matches[1]
+		}
+	}
+	if matches := orgClosedRegex.FindStringSubmatch(line); matches != nil {
+		if closed, ok := parseOrgTime("["+matches[1]+"]", loc); ok {
+			item.Completed = closed
+		}
+	}
+}
+
+// parseOrgProperty reads a line of a PROPERTIES drawer
+func parseOrgProperty(item *Item, line string, loc *time.Location) {
+	matches := orgPropertyRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return
+	}
+	value := strings.TrimSpace(matches[2])
+	switch strings.ToUpper(matches[1]) {
+	case "EFFORT_POINTS":
+		if effort, err := strconv.Atoi(value); err == nil {
+			item.Effort = effort
+		}
+	case "ARCHIVE_TIME":
+		if archived, ok := parseOrgTime("["+value+"]", loc); ok && item.Completed.IsZero() {
+			item.Completed = archived
+		}
+	case "ARCHIVE_OLPATH":
+		if parts := strings.Split(value, "/"); value != "" {
+			item.Section = strings.TrimSpace(parts[len(parts)-1])
+		}
+	}
+}
+
+// appendOrgTime appends the time of an inactive timestamp to times
+func appendOrgTime(times *[]time.Time, stamp string, loc *time.Location) {
+	if t, ok := parseOrgTime(stamp, loc); ok {
+		*times = append(*times, t)
+	}
+}
+
+// parseOrgTime parses an inactive timestamp such as [2024-03-05 Tue 10:00]
+func parseOrgTime(stamp string, loc *time.Location) (time.Time, bool) {
+	matches := orgInactiveRegex.FindStringSubmatch(stamp)
+	if matches == nil {
+		return time.Time{}, false
+	}
+	layout, value := "2006-01-02", matches[1]
+	if matches[2] != "" {
+		layout, value = "2006-01-02 15:04", matches[1]+" "+matches[2]
+	}
+	t, err := time.ParseInLocation(layout, value, loc)
+	return t, err == nil
+}
+
+// containsTag reports whether tags contains tag, ignoring case
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+func TestOrg(t *testing.T) {
+	content := `#+TITLE: Tasks
+* Home
+** NEXT [#B] Fix the bike :errands:
+DEADLINE: <2024-03-09 Sat> SCHEDULED: <2024-03-08 Fri>
+:PROPERTIES:
+:EFFORT_POINTS: 3
+:ID: abc
+:END:
+:LOGBOOK:
+- State "STARTED"    from "TODO"       [2024-03-04 Mon 09:30]
+CLOCK: [2024-03-03 Sun 10:00]--[2024-03-03 Sun 11:00] =>  1:00
+:END:
+Buy a chain first.
+- check the brakes
+*** DONE oil the chain
+*** CANCELLED paint it
+**** Notes
+** CANCELLED Old idea
+- ignored
+** WAITING Hear back from landlord
+* Work
+** DONE [#A] Ship release
+CLOSED: [2024-03-05 Tue 17:45]
+* Archive :ARCHIVE:
+** DONE Renew passport :travel:
+:PROPERTIES:
+:ARCHIVE_TIME: 2024-02-01 Thu 08:00
+:ARCHIVE_OLPATH: Personal/Travel
+:END:
+`
+
+	items := Org(content, time.UTC)
+	if len(items) != 4 {
+		t.Fatalf("got %d items, want 4: %+v", len(items), items)
+	}
+
+	bike := items[0]
+	if got, want := bike.Line(), "- [ ] !! B3 Fix the bike #errands DEADLINE: <2024-03-09 Sat>"; got != want {
+		t.Errorf("bike Line() = %q, want %q", got, want)
+	}
+	if bike.Section != "Home" || len(bike.Worked) != 2 || len(bike.Details) != 2 || bike.Details[0] != "Buy a chain first." {
+		t.Errorf("bike = %+v", bike)
+	}
+	if len(bike.Subtasks) != 1 || bike.Subtasks[0].Line() != "- [x] oil the chain" {
+		t.Errorf("bike subtasks = %+v", bike.Subtasks)
+	}
+
+	if landlord := items[1]; landlord.Status != "b" || landlord.Section != "Home" {
+		t.Errorf("landlord = %+v", landlord)
+	}
+
+	ship := items[2]
+	if !ship.IsCompleted() || ship.Priority != task.PriorityCritical || ship.Section != "Work" ||
+		!ship.Completed.Equal(time.Date(2024, 3, 5, 17, 45, 0, 0, time.UTC)) {
+		t.Errorf("ship = %+v", ship)
+	}
+
+	passport := items[3]
+	if !passport.IsCompleted() || passport.Section != "Travel" || !passport.Completed.Equal(time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("passport = %+v", passport)
+	}
+}
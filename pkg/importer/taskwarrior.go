@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+	"github.com/robertarles/taskmasterra/v2/pkg/taskwarrior"
+)
+
+// CriticalUrgency is the Taskwarrior urgency from which an open task is
+// imported with priority A, whatever its own priority
+const CriticalUrgency = 15.0
+
+// Taskwarrior converts the tasks of a `task export`. Deleted tasks and the
+// templates of repeating tasks are skipped. H, M and L priorities map to B, C
+// and D, the project is the section, annotations are details, started tasks
+// are active (!!) and the "blocked" tag marks blocked tasks [b]. The start and
+// end times are journaled, and completed tasks are archived at their end
+// time. Dates are converted to loc; a due time at midnight is a whole-day date.
+func Taskwarrior(tasks []taskwarrior.Task, loc *time.Location) []Item {
+	var items []Item
+	for _, tw := range tasks {
+		if tw.Status == taskwarrior.StatusDeleted || tw.Status == taskwarrior.StatusRecurring {
+			continue
+		}
+
+		item := Item{
+			Title:    strings.TrimSpace(tw.Description),
+			Status:   " ",
+			Priority: taskwarriorPriority(tw.Priority),
+			Effort:   tw.Effort,
+			Section:  tw.Project,
+		}
+		for _, tag := range tw.Tags {
+			if tag == "blocked" {
+				item.Status = "b"
+				continue
+			}
+			item.Tags = append(item.Tags, tag)
+		}
+		for _, annotation := range tw.Annotations {
+			item.Details = append(item.Details, annotation.Description)
+		}
+		if tw.Due != nil {
+			due := tw.Due.In(loc)
+			item.Date = &task.Date{Time: due, HasTime: due.Hour() != 0 || due.Minute() != 0}
+			if interval, unit, ok := taskwarrior.ParseRecur(tw.Recur); ok {
+				item.Date.Repeater = &task.Repeater{Kind: "+", Interval: interval, Unit: unit}
+			}
+		}
+		if tw.Start != nil {
+			item.Worked = append(item.Worked, tw.Start.In(loc))
+		}
+
+		if tw.Status == taskwarrior.StatusCompleted {
+			item.Status = "x"
+			if tw.End != nil {
+				item.Completed = tw.End.In(loc)
+				item.Worked = append(item.Worked, item.Completed)
+			}
+		} else {
+			item.Active = tw.Start != nil
+			if tw.Urgency >= CriticalUrgency {
+				item.Priority = task.PriorityCritical
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// taskwarriorPriority maps a Taskwarrior priority to a task priority
+func taskwarriorPriority(priority string) task.Priority {
+	switch priority {
+	case taskwarrior.PriorityHigh:
+		return task.PriorityHigh
+	case taskwarrior.PriorityMedium:
+		return task.PriorityMedium
+	case taskwarrior.PriorityLow:
+		return task.PriorityLow
+	default:
+		return task.PriorityNone
+	}
+}
@@ -0,0 +1,94 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+	"github.com/robertarles/taskmasterra/v2/pkg/taskwarrior"
+)
+
+func TestTaskwarrior(t *testing.T) {
+	at := func(day, hour int) *taskwarrior.Time {
+		return taskwarrior.NewTime(time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC))
+	}
+	tasks := []taskwarrior.Task{
+		{Description: "Write report", Status: taskwarrior.StatusPending, Priority: "M", Project: "Work", Tags: []string{"docs"},
+			Due: at(9, 0), Recur: "weekly", Start: at(4, 9), Annotations: []taskwarrior.Annotation{{Description: "outline done"}}},
+		{Description: "Fix outage", Status: taskwarrior.StatusWaiting, Priority: "L", Tags: []string{"blocked"}, Urgency: 16.2, Due: at(6, 14)},
+		{Description: "Pay rent", Status: taskwarrior.StatusCompleted, Priority: "H", Effort: 2, Start: at(4, 8), End: at(5, 10)},
+		{Description: "Gone", Status: taskwarrior.StatusDeleted},
+		{Description: "Template", Status: taskwarrior.StatusRecurring},
+	}
+
+	items := Taskwarrior(tasks, time.UTC)
+	if len(items) != 3 {
+		t.Fatalf("got %d items, want 3: %+v", len(items), items)
+	}
+
+	if got, want := items[0].Line(), "- [ ] !! C1 Write report #docs <2024-03-09 Sat +1w>"; got != want {
+		t.Errorf("items[0].Line() = %q, want %q", got, want)
+	}
+	if items[0].Section != "Work" || len(items[0].Details) != 1 || len(items[0].Worked) != 1 {
+		t.Errorf("items[0] = %+v", items[0])
+	}
+	if got, want := items[1].Line(), "- [b] A1 Fix outage <2024-03-06 Wed 14:00>"; got != want {
+		t.Errorf("items[1].Line() = %q, want %q", got, want)
+	}
+	third := items[2]
+	if third.Line() != "- [x] B2 Pay rent" || !third.Completed.Equal(at(5, 10).Time) || len(third.Worked) != 2 {
+		t.Errorf("items[2] = %+v, line %q", third, third.Line())
+	}
+}
+
+func TestImportTaskwarriorHistory(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "taskmasterra-import-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	todoPath := filepath.Join(tempDir, "todo.md")
+	if err := os.WriteFile(todoPath, []byte("## Home\n"), 0644); err != nil {
+		t.Fatalf("Failed to write todo file: %v", err)
+	}
+
+	// A repeating task completed twice, started before the first completion
+	at := func(day int) *taskwarrior.Time {
+		return taskwarrior.NewTime(time.Date(2024, 3, day, 9, 0, 0, 0, time.UTC))
+	}
+	tasks := []taskwarrior.Task{
+		{Description: "Water plants", Status: taskwarrior.StatusCompleted, Project: "Home", Start: at(1), End: at(2)},
+		{Description: "Water plants", Status: taskwarrior.StatusCompleted, Project: "Home", End: at(9)},
+	}
+	opts := task.DefaultProcessOptions()
+
+	result, err := Import(todoPath, Taskwarrior(tasks, time.UTC), opts)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if want := (Result{Archived: 2, Journaled: 3}); *result != want {
+		t.Errorf("Import() = %+v, want %+v", *result, want)
+	}
+
+	jm := journal.NewManager(todoPath)
+	archived, _ := jm.ReadArchive(opts.Timestamp)
+	journaled, _ := jm.ReadJournal(opts.Timestamp)
+	if len(archived) != 2 || archived[0].Section != "Home" {
+		t.Errorf("archive = %+v", archived)
+	}
+	if len(journaled) != 3 {
+		t.Errorf("journal = %+v", journaled)
+	}
+
+	result, err = Import(todoPath, Taskwarrior(tasks, time.UTC), opts)
+	if err != nil {
+		t.Fatalf("second Import() error = %v", err)
+	}
+	if want := (Result{Skipped: 2}); *result != want {
+		t.Errorf("second Import() = %+v, want %+v", *result, want)
+	}
+}
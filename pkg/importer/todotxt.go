@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+// Precompiled regex patterns for better performance
+var (
+	todoTxtDateRegex     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	todoTxtPriorityRegex = regexp.MustCompile(`^\(([A-Z])\)$`)
+	todoTxtRecRegex      = regexp.MustCompile(`^(\+?)(\d+)([dwmy])$`)
+)
+
+// TodoTxt reads the tasks of a todo.txt file. Priorities (A) to (D) map to
+// A to D, the first +project is the section (with _ read as a space), other
+// projects and @contexts become #tags, due: is the date and rec: its
+// repeater, and "x" marks completed items with an optional completion date.
+// The pri: and effort: keys written by export are read back. Dates are
+// interpreted in loc.
+func TodoTxt(content string, loc *time.Location) []Item {
+	var items []Item
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		items = append(items, parseTodoTxtLine(line, loc))
+	}
+	return items
+}
+
+// parseTodoTxtLine parses a single todo.txt task
+func parseTodoTxtLine(line string, loc *time.Location) Item {
+	item := Item{Status: " "}
+	fields := strings.Fields(line)
+
+	if len(fields) > 0 && fields[0] == "x" {
+		item.Status = "x"
+		fields = fields[1:]
+		if len(fields) > 0 && todoTxtDateRegex.MatchString(fields[0]) {
+			item.Completed, _ = time.ParseInLocation("2006-01-02", fields[0], loc)
+			fields = fields[1:]
+		}
+	} else if len(fields) > 0 && todoTxtPriorityRegex.MatchString(fields[0]) {
+		item.Priority = task.PriorityForLetter(fields[0][1:2])
+		fields = fields[1:]
+	}
+	// creation date
+	if len(fields) > 0 && todoTxtDateRegex.MatchString(fields[0]) {
+		fields = fields[1:]
+	}
+
+	var title []string
+	var due time.Time
+	var repeater *task.Repeater
+	for _, field := range fields {
+		key, value, isKeyValue := strings.Cut(field, ":")
+		switch {
+		case strings.HasPrefix(field, "+") && len(field) > 1:
+			project := strings.ReplaceAll(field[1:], "_", " ")
+			if item.Section == "" {
+				item.Section = project
+			} else {
+				item.Tags = append(item.Tags, field[1:])
+			}
+		case strings.HasPrefix(field, "@") && len(field) > 1:
+			title = append(title, "#"+field[1:])
+		case isKeyValue && key == "due" && todoTxtDateRegex.MatchString(value):
+			due, _ = time.ParseInLocation("2006-01-02", value, loc)
+		case isKeyValue && key == "rec" && todoTxtRecRegex.MatchString(value):
+			matches := todoTxtRecRegex.FindStringSubmatch(value)
+			interval, _ := strconv.Atoi(matches[2])
+			kind := ".+" // todo.txt repeats from the completion date by default
+			if matches[1] == "+" {
+				kind = "+"
+			}
+			repeater = &task.Repeater{Kind: kind, Interval: interval, Unit: matches[3][0]}
+		case isKeyValue && key == "pri" && len(value) == 1:
+			item.Priority = task.PriorityForLetter(value)
+		case isKeyValue && key == "effort":
+			if effort, err := strconv.Atoi(value); err == nil {
+				item.Effort = effort
+			}
+		default:
+			title = append(title, field)
+		}
+	}
+
+	item.Title = strings.Join(title, " ")
+	if !due.IsZero() {
+		item.Date = &task.Date{Time: due, Repeater: repeater}
+	}
+	return item
+}
@@ -0,0 +1,50 @@
+package importer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+func TestTodoTxt(t *testing.T) {
+	content := "(A) 2024-03-01 Call @phone mom +Family_Stuff +calls due:2024-03-09 rec:+1w effort:2\n" +
+		"\n" +
+		"x 2024-03-05 2024-03-01 Pay rent +Home pri:B\n" +
+		"(F) Read about key:value pairs\n" +
+		"x Done without dates\n"
+
+	items := TodoTxt(content, time.UTC)
+	if len(items) != 4 {
+		t.Fatalf("got %d items, want 4: %+v", len(items), items)
+	}
+
+	first := items[0]
+	if first.Title != "Call #phone mom" || first.Section != "Family Stuff" || first.Priority != task.PriorityCritical || first.Effort != 2 {
+		t.Errorf("first item = %+v", first)
+	}
+	if len(first.Tags) != 1 || first.Tags[0] != "calls" || first.IsCompleted() {
+		t.Errorf("first item = %+v", first)
+	}
+	if first.Date == nil || first.Date.String() != "<2024-03-09 Sat +1w>" {
+		t.Errorf("first item date = %v", first.Date)
+	}
+	if got, want := first.Line(), "- [ ] A2 Call #phone mom #calls <2024-03-09 Sat +1w>"; got != want {
+		t.Errorf("first item Line() = %q, want %q", got, want)
+	}
+
+	second := items[1]
+	if !second.IsCompleted() || second.Title != "Pay rent" || second.Section != "Home" || second.Priority != task.PriorityHigh {
+		t.Errorf("second item = %+v", second)
+	}
+	if !second.Completed.Equal(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("second item completed = %v", second.Completed)
+	}
+
+	if third := items[2]; third.Priority != task.PriorityNone || third.Title != "Read about key:value pairs" {
+		t.Errorf("third item = %+v", third)
+	}
+	if fourth := items[3]; !fourth.IsCompleted() || !fourth.Completed.IsZero() || fourth.Title != "Done without dates" {
+		t.Errorf("fourth item = %+v", fourth)
+	}
+}
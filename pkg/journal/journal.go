@@ -1,20 +1,151 @@
 package journal
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
+)
+
+// DefaultTimestampLayout is the layout used when no layout is configured.
+// In UTC it produces the same text as the original fixed "[... UTC]" stamps.
+const DefaultTimestampLayout = "2006-01-02 15:04:05 MST"
+
+// legacyTimestampLayout is the fixed layout written by earlier releases.
+const legacyTimestampLayout = "2006-01-02 15:04:05 UTC"
+
+// fallbackTimestampLayouts are tried when reading entries whose stamp does
+// not match the configured layout, e.g. after the layout has been changed.
+var fallbackTimestampLayouts = []string{
+	legacyTimestampLayout,
+	DefaultTimestampLayout,
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	time.RFC3339,
+	"2006-01-02",
+}
 
-	"github.com/robertarles/taskmasterra/v2/pkg/utils"
+// Precompiled regex patterns for better performance
+var (
+	timestampPrefixRegex = regexp.MustCompile(`^\[([^\]]+)\] ?`)
+	sectionCommentRegex  = regexp.MustCompile(`\s*<!-- section: (.*?) -->\s*$`)
 )
 
+// TimestampFormat controls how journal and archive entries are stamped
+type TimestampFormat struct {
+	Location *time.Location
+	Layout   string
+}
+
+// DefaultTimestampFormat returns the UTC timestamp format used by earlier releases
+func DefaultTimestampFormat() TimestampFormat {
+	return TimestampFormat{Location: time.UTC, Layout: DefaultTimestampLayout}
+}
+
+// NewTimestampFormat creates a timestamp format from a timezone name and layout.
+// The timezone may be an IANA name such as "Europe/Berlin", "Local" or empty for UTC.
+// An empty layout selects DefaultTimestampLayout.
+func NewTimestampFormat(timezone, layout string) (TimestampFormat, error) {
+	format := DefaultTimestampFormat()
+
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return format, fmt.Errorf("unknown timezone '%s': %w", timezone, err)
+		}
+		format.Location = loc
+	}
+
+	if layout != "" {
+		if strings.ContainsAny(layout, "[]\n") {
+			return format, fmt.Errorf("timestamp layout '%s' must not contain brackets or newlines", layout)
+		}
+		// The date must survive a round trip so that entries can be read back
+		reference := time.Date(2006, time.January, 2, 15, 4, 5, 0, format.Location)
+		parsed, err := time.ParseInLocation(layout, reference.Format(layout), format.Location)
+		if err != nil {
+			return format, fmt.Errorf("timestamp layout '%s' cannot be parsed back: %w", layout, err)
+		}
+		if parsed.Year() != 2006 || parsed.Month() != time.January || parsed.Day() != 2 {
+			return format, fmt.Errorf("timestamp layout '%s' must include the year, month and day", layout)
+		}
+		format.Layout = layout
+	}
+
+	return format, nil
+}
+
+// Format returns the bracketed timestamp for t in the configured location
+func (f TimestampFormat) Format(t time.Time) string {
+	return "[" + t.In(f.location()).Format(f.layout()) + "]"
+}
+
+// Now returns the bracketed timestamp for the current time
+func (f TimestampFormat) Now() string {
+	return f.Format(time.Now())
+}
+
+// SplitTimestamp separates a leading timestamp from a journal or archive line.
+// Both the configured layout and the layouts written by earlier releases are
+// accepted. It returns the parsed time, the remainder of the line and whether
+// a timestamp was found.
+func (f TimestampFormat) SplitTimestamp(line string) (time.Time, string, bool) {
+	matches := timestampPrefixRegex.FindStringSubmatch(line)
+	if len(matches) < 2 {
+		return time.Time{}, line, false
+	}
+
+	stamp := matches[1]
+	rest := line[len(matches[0]):]
+	layouts := append([]string{f.layout()}, fallbackTimestampLayouts...)
+	for i, layout := range layouts {
+		loc := f.location()
+		if i > 0 && layout == legacyTimestampLayout {
+			// "UTC" is literal text in the legacy layout, not a zone to parse
+			loc = time.UTC
+		}
+		parsed, err := time.ParseInLocation(layout, stamp, loc)
+		if err == nil {
+			return parsed, rest, true
+		}
+	}
+
+	return time.Time{}, line, false
+}
+
+// location returns the configured location, defaulting to UTC
+func (f TimestampFormat) location() *time.Location {
+	if f.Location == nil {
+		return time.UTC
+	}
+	return f.Location
+}
+
+// layout returns the configured layout, defaulting to DefaultTimestampLayout
+func (f TimestampFormat) layout() string {
+	if f.Layout == "" {
+		return DefaultTimestampLayout
+	}
+	return f.Layout
+}
+
 // Manager handles journal and archive operations
 type Manager struct {
 	JournalPath  string
 	ArchivePath  string
 	OriginalPath string
+
+	// Layout selects how new entries are written, LayoutPrepend or LayoutAppend.
+	// A file that already uses the append layout keeps it.
+	Layout string
+
+	// Timestamp is used to recognize entries when converting between layouts
+	Timestamp TimestampFormat
 }
 
 // NewManager creates a new journal manager
@@ -27,6 +158,8 @@ func NewManager(filePath string) *Manager {
 		JournalPath:  filepath.Join(dirPath, baseName+".xjournal.md"),
 		ArchivePath:  filepath.Join(dirPath, baseName+".xarchive.md"),
 		OriginalPath: filePath,
+		Layout:       LayoutPrepend,
+		Timestamp:    DefaultTimestampFormat(),
 	}
 }
 
@@ -36,16 +169,7 @@ func (m *Manager) WriteToJournal(entries []string) error {
 		return nil
 	}
 
-	var existingContent string
-	if _, err := os.Stat(m.JournalPath); err == nil {
-		existingContent, err = utils.ReadFileContent(m.JournalPath)
-		if err != nil {
-			return fmt.Errorf("failed to read existing journal file '%s': %w", m.JournalPath, err)
-		}
-	}
-
-	newContent := strings.Join(entries, "\n") + "\n" + existingContent
-	if err := utils.WriteFileContent(m.JournalPath, newContent); err != nil {
+	if err := m.writeEntries(m.JournalPath, SourceJournal, entries); err != nil {
 		return fmt.Errorf("failed to write journal entries to '%s': %w", m.JournalPath, err)
 	}
 
@@ -58,16 +182,7 @@ func (m *Manager) WriteToArchive(entries []string) error {
 		return nil
 	}
 
-	var existingContent string
-	if _, err := os.Stat(m.ArchivePath); err == nil {
-		existingContent, err = utils.ReadFileContent(m.ArchivePath)
-		if err != nil {
-			return fmt.Errorf("failed to read existing archive file '%s': %w", m.ArchivePath, err)
-		}
-	}
-
-	newContent := strings.Join(entries, "\n") + "\n" + existingContent
-	if err := utils.WriteFileContent(m.ArchivePath, newContent); err != nil {
+	if err := m.writeEntries(m.ArchivePath, SourceArchive, entries); err != nil {
 		return fmt.Errorf("failed to write archive entries to '%s': %w", m.ArchivePath, err)
 	}
 
@@ -76,6 +191,238 @@ func (m *Manager) WriteToArchive(entries []string) error {
 
 // FormatTimestamp returns a formatted UTC timestamp
 func FormatTimestamp() string {
-	currentTime := time.Now().UTC()
-	return currentTime.Format("[2006-01-02 15:04:05 UTC]")
-} 
\ No newline at end of file
+	return DefaultTimestampFormat().Now()
+}
+
+// ParseTimestamp splits a leading timestamp from a line using the default format.
+// It accepts the legacy "[2006-01-02 15:04:05 UTC]" stamps as well as the
+// other layouts that NewTimestampFormat can produce.
+func ParseTimestamp(line string) (time.Time, string, bool) {
+	return DefaultTimestampFormat().SplitTimestamp(line)
+}
+
+// Entry sources
+const (
+	SourceJournal = "journal"
+	SourceArchive = "archive"
+)
+
+// Entry is a task read back from a journal or archive file
+type Entry struct {
+	Timestamp  time.Time
+	Line       string   // task line without its timestamp or section comment
+	Details    []string // detail lines without timestamps
+	Section    string   // header the task was under, if recorded
+	Source     string   // SourceJournal or SourceArchive
+	LineNumber int      // 1-based line number of the entry in its file
+	EndLine    int      // 1-based line number of the entry's last detail
+}
+
+// SectionComment returns the trailing comment that records a task's section
+// in the archive. Returns an empty string when there is no section.
+func SectionComment(section string) string {
+	if section == "" {
+		return ""
+	}
+	return " <!-- section: " + section + " -->"
+}
+
+// splitSectionComment separates a trailing section comment from a line
+func splitSectionComment(line string) (string, string) {
+	matches := sectionCommentRegex.FindStringSubmatchIndex(line)
+	if matches == nil {
+		return line, ""
+	}
+	return line[:matches[0]], line[matches[2]:matches[3]]
+}
+
+// ParseEntries parses journal or archive content into entries.
+// Journal details are written without timestamps, while archive details carry
+// the same timestamp as their parent, so the source decides how indented
+// timestamped lines are grouped. In the archive such a line is a detail only
+// when it directly follows a less indented line of an entry with the same
+// timestamp and has no section comment of its own; otherwise, like an
+// indented subtask archived on its own, it is an entry.
+func ParseEntries(content string, format TimestampFormat, source string) []Entry {
+	parser := newEntryParser(format, source)
+	for i, line := range strings.Split(content, "\n") {
+		parser.add(line, i+1)
+	}
+	return parser.entries
+}
+
+// entryParser groups journal or archive lines into entries one line at a time
+type entryParser struct {
+	format  TimestampFormat
+	source  string
+	entries []Entry
+	current int
+}
+
+// newEntryParser creates a parser for the given timestamp format and source
+func newEntryParser(format TimestampFormat, source string) *entryParser {
+	return &entryParser{format: format, source: source, current: -1}
+}
+
+// add parses the next line of the file
+func (p *entryParser) add(line string, lineNumber int) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	ts, rest, ok := p.format.SplitTimestamp(line)
+	indented := isIndented(rest)
+
+	switch {
+	case ok && indented && p.isArchiveDetail(ts, rest, lineNumber):
+		p.entries[p.current].Details = append(p.entries[p.current].Details, rest)
+		p.entries[p.current].EndLine = lineNumber
+	case ok:
+		taskLine, section := splitSectionComment(rest)
+		p.entries = append(p.entries, Entry{
+			Timestamp:  ts,
+			Line:       taskLine,
+			Section:    section,
+			Source:     p.source,
+			LineNumber: lineNumber,
+			EndLine:    lineNumber,
+		})
+		p.current = len(p.entries) - 1
+	case indented && p.current >= 0:
+		p.entries[p.current].Details = append(p.entries[p.current].Details, rest)
+		p.entries[p.current].EndLine = lineNumber
+	default:
+		p.current = -1
+	}
+}
+
+// isArchiveDetail reports whether an indented archive line with timestamp ts
+// is a detail of the current entry, as written by recordkeep: the next line
+// after the entry, more indented than its task line, with the same timestamp
+// and without a section comment of its own
+func (p *entryParser) isArchiveDetail(ts time.Time, rest string, lineNumber int) bool {
+	if p.source != SourceArchive || p.current < 0 {
+		return false
+	}
+	parent := p.entries[p.current]
+	if _, section := splitSectionComment(rest); section != "" {
+		return false
+	}
+	return indentation(rest) > indentation(parent.Line) && parent.Timestamp.Equal(ts) && lineNumber == parent.EndLine+1
+}
+
+// isIndented reports whether a line starts with a space or tab
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
+
+// indentation returns the length of the leading whitespace of a line
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// ReadJournal reads all entries from the journal file.
+// A missing journal file yields no entries.
+func (m *Manager) ReadJournal(format TimestampFormat) ([]Entry, error) {
+	return readEntries(m.JournalPath, format, SourceJournal)
+}
+
+// ReadArchive reads all entries from the archive file.
+// A missing archive file yields no entries.
+func (m *Manager) ReadArchive(format TimestampFormat) ([]Entry, error) {
+	return readEntries(m.ArchivePath, format, SourceArchive)
+}
+
+// readEntries streams and parses an entry file if it exists.
+// Entry files are not subject to utils.MaxFileSize since they grow without bound.
+func readEntries(path string, format TimestampFormat, source string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s file '%s': %w", source, path, err)
+	}
+	defer file.Close()
+
+	parser := newEntryParser(format, source)
+	scanner := newLineScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		parser.add(scanner.Text(), lineNumber)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s file '%s': %w", source, path, err)
+	}
+
+	return parser.entries, nil
+}
+
+// RemoveArchiveEntry removes an entry previously read with ReadArchive from
+// the archive file. It returns the removed lines, which InsertArchiveLines
+// can put back.
+func (m *Manager) RemoveArchiveEntry(entry Entry) ([]string, error) {
+	if entry.LineNumber < 1 || entry.EndLine < entry.LineNumber {
+		return nil, fmt.Errorf("archive entry at lines %d-%d is out of range for '%s'", entry.LineNumber, entry.EndLine, m.ArchivePath)
+	}
+
+	var removed []string
+	err := rewriteFile(m.ArchivePath, func(w io.Writer, lines *bufio.Scanner) error {
+		for lineNumber := 1; lines.Scan(); lineNumber++ {
+			if lineNumber >= entry.LineNumber && lineNumber <= entry.EndLine {
+				removed = append(removed, lines.Text())
+				continue
+			}
+			if _, err := io.WriteString(w, lines.Text()+"\n"); err != nil {
+				return err
+			}
+		}
+		if len(removed) != entry.EndLine-entry.LineNumber+1 {
+			return fmt.Errorf("archive entry at lines %d-%d is out of range", entry.LineNumber, entry.EndLine)
+		}
+		return lines.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update archive file '%s': %w", m.ArchivePath, err)
+	}
+
+	return removed, nil
+}
+
+// InsertArchiveLines inserts lines into the archive file before lineNumber,
+// or at its end when it is shorter, e.g. to undo RemoveArchiveEntry
+func (m *Manager) InsertArchiveLines(lineNumber int, inserted []string) error {
+	err := rewriteFile(m.ArchivePath, func(w io.Writer, lines *bufio.Scanner) error {
+		write := func(text string) error {
+			_, err := io.WriteString(w, text+"\n")
+			return err
+		}
+		done := false
+		for n := 1; lines.Scan(); n++ {
+			if n == lineNumber {
+				for _, line := range inserted {
+					if err := write(line); err != nil {
+						return err
+					}
+				}
+				done = true
+			}
+			if err := write(lines.Text()); err != nil {
+				return err
+			}
+		}
+		if err := lines.Err(); err != nil || done {
+			return err
+		}
+		for _, line := range inserted {
+			if err := write(line); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update archive file '%s': %w", m.ArchivePath, err)
+	}
+
+	return nil
+}
@@ -258,4 +258,282 @@ func TestFormatTimestamp(t *testing.T) {
 	if _, err := time.Parse("2006-01-02 15:04:05", trimmed[:19]); err != nil {
 		t.Errorf("Timestamp does not parse as time: %v", err)
 	}
-} 
\ No newline at end of file
+} 
+
+func TestNewTimestampFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		timezone  string
+		layout    string
+		wantErr   bool
+		wantLayout string
+	}{
+		{"Defaults", "", "", false, DefaultTimestampLayout},
+		{"IANA timezone", "America/Denver", "", false, DefaultTimestampLayout},
+		{"Local timezone", "Local", "", false, DefaultTimestampLayout},
+		{"Custom layout", "UTC", "2006-01-02 15:04 -0700", false, "2006-01-02 15:04 -0700"},
+		{"Unknown timezone", "Mars/Olympus", "", true, ""},
+		{"Layout without date", "UTC", "15:04:05", true, ""},
+		{"Layout with brackets", "UTC", "[2006-01-02]", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := NewTimestampFormat(tt.timezone, tt.layout)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTimestampFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && format.Layout != tt.wantLayout {
+				t.Errorf("Layout = %q, want %q", format.Layout, tt.wantLayout)
+			}
+		})
+	}
+}
+
+func TestTimestampFormat_Format(t *testing.T) {
+	moment := time.Date(2024, time.March, 5, 3, 30, 0, 0, time.UTC)
+
+	if got := DefaultTimestampFormat().Format(moment); got != "[2024-03-05 03:30:00 UTC]" {
+		t.Errorf("Default format = %q, want legacy UTC format", got)
+	}
+
+	denver, err := NewTimestampFormat("America/Denver", "2006-01-02 15:04 MST")
+	if err != nil {
+		t.Fatalf("NewTimestampFormat failed: %v", err)
+	}
+	// 03:30 UTC is still the previous evening in Denver
+	if got := denver.Format(moment); got != "[2024-03-04 20:30 MST]" {
+		t.Errorf("Denver format = %q, want %q", got, "[2024-03-04 20:30 MST]")
+	}
+}
+
+func TestTimestampFormat_SplitTimestamp(t *testing.T) {
+	custom, err := NewTimestampFormat("America/Denver", "02.01.2006 15:04")
+	if err != nil {
+		t.Fatalf("NewTimestampFormat failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		line     string
+		wantOK   bool
+		wantDate string
+		wantRest string
+	}{
+		{"Legacy UTC stamp", "[2024-03-05 03:30:00 UTC] - [x] Task", true, "2024-03-05", "- [x] Task"},
+		{"Configured layout", "[04.03.2024 20:30] - [W] Task", true, "2024-03-04", "- [W] Task"},
+		{"Numeric offset", "[2024-03-04 20:30:00 -0700] - [W] Task", true, "2024-03-04", "- [W] Task"},
+		{"Indented detail", "[2024-03-05 03:30:00 UTC]   - detail", true, "2024-03-05", "  - detail"},
+		{"No stamp", "- [x] Task", false, "", "- [x] Task"},
+		{"Not a timestamp", "[[wiki link]] - note", false, "", "[[wiki link]] - note"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, rest, ok := custom.SplitTimestamp(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("SplitTimestamp() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if rest != tt.wantRest {
+				t.Errorf("SplitTimestamp() rest = %q, want %q", rest, tt.wantRest)
+			}
+			if ok && ts.Format("2006-01-02") != tt.wantDate {
+				t.Errorf("SplitTimestamp() date = %s, want %s", ts.Format("2006-01-02"), tt.wantDate)
+			}
+		})
+	}
+}
+
+func TestTimestampFormat_SplitTimestampKeepsLegacyUTC(t *testing.T) {
+	denver, err := NewTimestampFormat("America/Denver", "2006-01-02 15:04")
+	if err != nil {
+		t.Fatalf("NewTimestampFormat failed: %v", err)
+	}
+
+	tests := []struct {
+		line string
+		want time.Time
+	}{
+		// written in UTC before a timezone was configured
+		{"[2024-03-04 10:00:00 UTC] - [x] Old task", time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)},
+		// written with the configured layout, in Denver (UTC-7)
+		{"[2024-03-04 10:00] - [x] New task", time.Date(2024, 3, 4, 17, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		ts, _, ok := denver.SplitTimestamp(tt.line)
+		if !ok || !ts.Equal(tt.want) {
+			t.Errorf("SplitTimestamp(%q) = %v, %v, want %v", tt.line, ts.UTC(), ok, tt.want)
+		}
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	ts, rest, ok := ParseTimestamp(FormatTimestamp() + " - [X] Task")
+	if !ok {
+		t.Fatal("ParseTimestamp() could not read back FormatTimestamp()")
+	}
+	if rest != "- [X] Task" {
+		t.Errorf("ParseTimestamp() rest = %q", rest)
+	}
+	if time.Since(ts) > time.Minute {
+		t.Errorf("ParseTimestamp() returned unexpected time %v", ts)
+	}
+}
+
+func TestParseEntries(t *testing.T) {
+	format := DefaultTimestampFormat()
+
+	journalContent := `[2024-03-05 10:00:00 UTC] - [W] Write report #work
+  - drafted intro
+  - [ ] send to review
+[2024-03-05 10:00:00 UTC]   - [W] standalone subtask
+[2024-03-04 09:00:00 UTC] - [X] Old task
+`
+	entries := ParseEntries(journalContent, format, SourceJournal)
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 journal entries, got %d: %+v", len(entries), entries)
+	}
+	if entries[0].Line != "- [W] Write report #work" || len(entries[0].Details) != 2 {
+		t.Errorf("Unexpected first journal entry: %+v", entries[0])
+	}
+	if entries[1].Line != "  - [W] standalone subtask" || entries[1].LineNumber != 4 {
+		t.Errorf("Indented timestamped journal line should be its own entry: %+v", entries[1])
+	}
+	if entries[0].Source != SourceJournal {
+		t.Errorf("Expected source %q, got %q", SourceJournal, entries[0].Source)
+	}
+
+	archiveContent := `[2024-03-05 10:00:00 UTC] - [x] Done task
+[2024-03-05 10:00:00 UTC]   - detail one
+[2024-03-05 10:00:00 UTC]   - detail two
+[2024-03-04 09:00:00 UTC] - [X] Older task
+`
+	entries = ParseEntries(archiveContent, format, SourceArchive)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 archive entries, got %d: %+v", len(entries), entries)
+	}
+	if len(entries[0].Details) != 2 || entries[0].Details[1] != "  - detail two" {
+		t.Errorf("Archive details not grouped with parent: %+v", entries[0])
+	}
+	if entries[1].Timestamp.Day() != 4 {
+		t.Errorf("Expected second archive entry on the 4th, got %v", entries[1].Timestamp)
+	}
+
+	// Indented lines with the same timestamp that are not details of the task above
+	archiveContent = `[2024-03-05 10:00:00 UTC] - [x] Parent one <!-- section: Work -->
+[2024-03-05 10:00:00 UTC]   - [x] Subtask archived alone <!-- section: Home -->
+[2024-03-05 10:00:00 UTC]     - its detail
+[2024-03-05 10:00:00 UTC] - [x] Parent two
+
+[2024-03-05 10:00:00 UTC]   - [x] After a gap
+`
+	entries = ParseEntries(archiveContent, format, SourceArchive)
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 archive entries, got %d: %+v", len(entries), entries)
+	}
+	if len(entries[0].Details) != 0 || entries[1].Line != "  - [x] Subtask archived alone" || entries[1].Section != "Home" {
+		t.Errorf("Subtask with its own section comment grouped with the task above: %+v", entries[:2])
+	}
+	if len(entries[1].Details) != 1 || entries[1].Details[0] != "    - its detail" {
+		t.Errorf("More indented line not grouped with the subtask above: %+v", entries[1])
+	}
+	if len(entries[2].Details) != 0 || entries[3].Line != "  - [x] After a gap" {
+		t.Errorf("Line after a blank line grouped with the task above: %+v", entries[2:])
+	}
+}
+
+func TestReadJournalAndArchive(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "journal-read-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	jm := NewManager(filepath.Join(tmpDir, "todo.md"))
+	format := DefaultTimestampFormat()
+
+	// Missing files yield no entries
+	entries, err := jm.ReadJournal(format)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("ReadJournal() on missing file = %v, %v", entries, err)
+	}
+
+	if err := jm.WriteToArchive([]string{format.Now() + " - [x] Archived"}); err != nil {
+		t.Fatalf("WriteToArchive failed: %v", err)
+	}
+	entries, err = jm.ReadArchive(format)
+	if err != nil {
+		t.Fatalf("ReadArchive failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Source != SourceArchive {
+		t.Errorf("Unexpected archive entries: %+v", entries)
+	}
+}
+
+func TestSectionComment(t *testing.T) {
+	if got := SectionComment(""); got != "" {
+		t.Errorf("SectionComment(\"\") = %q, want empty", got)
+	}
+
+	line := "[2024-03-05 10:00:00 UTC] - [x] Task" + SectionComment("Work Items")
+	entries := ParseEntries(line, DefaultTimestampFormat(), SourceArchive)
+	if len(entries) != 1 || entries[0].Section != "Work Items" || entries[0].Line != "- [x] Task" {
+		t.Errorf("Section comment not parsed: %+v", entries)
+	}
+}
+
+func TestRemoveArchiveEntry(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "archive-remove-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	jm := NewManager(filepath.Join(tmpDir, "todo.md"))
+	content := `[2024-03-05 10:00:00 UTC] - [x] Keep me
+[2024-03-04 10:00:00 UTC] - [x] Remove me
+[2024-03-04 10:00:00 UTC]   - my detail
+[2024-03-03 10:00:00 UTC] - [x] Keep me too
+`
+	if err := os.WriteFile(jm.ArchivePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+
+	entries, err := jm.ReadArchive(DefaultTimestampFormat())
+	if err != nil {
+		t.Fatalf("ReadArchive failed: %v", err)
+	}
+	removed, err := jm.RemoveArchiveEntry(entries[1])
+	if err != nil {
+		t.Fatalf("RemoveArchiveEntry failed: %v", err)
+	}
+	if len(removed) != 2 || removed[1] != "[2024-03-04 10:00:00 UTC]   - my detail" {
+		t.Errorf("RemoveArchiveEntry returned %q, want the two lines of the entry", removed)
+	}
+
+	updated, err := os.ReadFile(jm.ArchivePath)
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+	want := "[2024-03-05 10:00:00 UTC] - [x] Keep me\n[2024-03-03 10:00:00 UTC] - [x] Keep me too\n"
+	if string(updated) != want {
+		t.Errorf("Archive after removal = %q, want %q", updated, want)
+	}
+
+	if _, err := jm.RemoveArchiveEntry(Entry{LineNumber: 10, EndLine: 12}); err == nil {
+		t.Error("Expected error for out of range entry")
+	}
+
+	if err := jm.InsertArchiveLines(2, removed); err != nil {
+		t.Fatalf("InsertArchiveLines failed: %v", err)
+	}
+	if restored, _ := os.ReadFile(jm.ArchivePath); string(restored) != content {
+		t.Errorf("Archive after InsertArchiveLines = %q, want %q", restored, content)
+	}
+	if err := jm.InsertArchiveLines(99, []string{"[2024-03-01 10:00:00 UTC] - [x] Appended"}); err != nil {
+		t.Fatalf("InsertArchiveLines failed: %v", err)
+	}
+	if appended, _ := os.ReadFile(jm.ArchivePath); !strings.HasSuffix(string(appended), "Keep me too\n[2024-03-01 10:00:00 UTC] - [x] Appended\n") {
+		t.Errorf("InsertArchiveLines past the end = %q, want the line appended", appended)
+	}
+}
@@ -0,0 +1,399 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/utils"
+)
+
+// Journal and archive file layouts
+const (
+	// LayoutPrepend keeps the newest entries at the top of the file.
+	// Every write streams the existing history into a new file.
+	LayoutPrepend = "prepend"
+	// LayoutAppend keeps the newest entries at the bottom of the file.
+	// Writes only append the new entries, so they cost O(new entries).
+	LayoutAppend = "append"
+)
+
+// AppendLayoutMarker is the first line of files that use the append layout
+const AppendLayoutMarker = "<!-- taskmasterra: layout=append -->"
+
+// maxLineLength bounds the length of a single journal line when streaming
+const maxLineLength = 1024 * 1024
+
+// reverseChunkSize is the number of bytes ReverseScanner reads at a time
+const reverseChunkSize = 64 * 1024
+
+// ValidLayout reports whether layout is a known journal layout
+func ValidLayout(layout string) bool {
+	return layout == LayoutPrepend || layout == LayoutAppend
+}
+
+// DetectLayout returns the layout of an existing journal or archive file.
+// Missing files and files without the append marker use LayoutPrepend.
+func DetectLayout(path string) (string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return LayoutPrepend, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to open '%s': %w", path, err)
+	}
+	defer file.Close()
+
+	firstLine, err := bufio.NewReader(file).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+	if strings.TrimSpace(firstLine) == AppendLayoutMarker {
+		return LayoutAppend, nil
+	}
+	return LayoutPrepend, nil
+}
+
+// writeEntries writes new entries using the manager's layout, or the append
+// layout if the file already uses it
+func (m *Manager) writeEntries(path, source string, entries []string) error {
+	layout, err := DetectLayout(path)
+	if err != nil {
+		return err
+	}
+
+	if m.Layout == LayoutAppend && layout != LayoutAppend {
+		if err := ConvertToAppendLayout(path, m.Timestamp, source); err != nil {
+			return err
+		}
+		layout = LayoutAppend
+	}
+
+	if layout == LayoutAppend {
+		return appendEntries(path, entries)
+	}
+	return prependEntries(path, entries)
+}
+
+// appendEntries appends entries to an append-layout file
+func appendEntries(path string, entries []string) error {
+	if err := utils.EnsureDirectoryExists(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, utils.DefaultFilePermission)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s' for appending: %w", path, err)
+	}
+
+	info, err := file.Stat()
+	if err == nil && info.Size() == 0 {
+		_, err = io.WriteString(file, AppendLayoutMarker+"\n")
+	}
+	if err == nil {
+		_, err = io.WriteString(file, strings.Join(entries, "\n")+"\n")
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to append to '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+// prependEntries writes entries at the top of a prepend-layout file.
+// The existing history is streamed rather than loaded into memory.
+func prependEntries(path string, entries []string) error {
+	return rewriteFile(path, func(w io.Writer, lines *bufio.Scanner) error {
+		if _, err := io.WriteString(w, strings.Join(entries, "\n")+"\n"); err != nil {
+			return err
+		}
+		for lines.Scan() {
+			if _, err := io.WriteString(w, lines.Text()+"\n"); err != nil {
+				return err
+			}
+		}
+		return lines.Err()
+	})
+}
+
+// ConvertToAppendLayout rewrites a prepend-layout file in the append layout.
+// Entries are reversed as whole blocks, so details stay below their parent;
+// source (SourceJournal or SourceArchive) decides which lines are details, as
+// for ParseEntries. This is a one-time O(history) operation; later writes only append.
+func ConvertToAppendLayout(path string, format TimestampFormat, source string) error {
+	if layout, err := DetectLayout(path); err != nil || layout == LayoutAppend {
+		return err
+	}
+
+	var preamble []string
+	var blocks [][]string
+	err := rewriteFile(path, func(w io.Writer, lines *bufio.Scanner) error {
+		// A block starts at every line that starts an entry as ParseEntries
+		// groups them, so details stay with their entry in either source
+		parser := newEntryParser(format, source)
+		for lineNumber := 1; lines.Scan(); lineNumber++ {
+			line := lines.Text()
+			count := len(parser.entries)
+			parser.add(line, lineNumber)
+			switch {
+			case len(parser.entries) > count:
+				blocks = append(blocks, []string{line})
+			case len(blocks) == 0:
+				preamble = append(preamble, line)
+			default:
+				blocks[len(blocks)-1] = append(blocks[len(blocks)-1], line)
+			}
+		}
+		if err := lines.Err(); err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(w, AppendLayoutMarker+"\n"); err != nil {
+			return err
+		}
+		for _, line := range preamble {
+			if _, err := io.WriteString(w, line+"\n"); err != nil {
+				return err
+			}
+		}
+		for i := len(blocks) - 1; i >= 0; i-- {
+			for _, line := range blocks[i] {
+				if strings.TrimSpace(line) == "" {
+					continue
+				}
+				if _, err := io.WriteString(w, line+"\n"); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to convert '%s' to the append layout: %w", path, err)
+	}
+
+	return nil
+}
+
+// rewriteFile streams an existing file (if any) through fn into a temporary
+// file, then replaces the original. The original is left untouched on error.
+func rewriteFile(path string, fn func(w io.Writer, lines *bufio.Scanner) error) error {
+	dir := filepath.Dir(path)
+	if err := utils.EnsureDirectoryExists(dir); err != nil {
+		return err
+	}
+
+	var source io.Reader = strings.NewReader("")
+	original, err := os.Open(path)
+	if err == nil {
+		defer original.Close()
+		source = original
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to open '%s': %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file in '%s': %w", dir, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	err = fn(writer, newLineScanner(source))
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), utils.DefaultFilePermission); err != nil {
+		return fmt.Errorf("failed to set permissions on '%s': %w", tmp.Name(), err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+// newLineScanner returns a line scanner that accepts long journal lines
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+	return scanner
+}
+
+// ReverseScanner reads the lines of a file from the last to the first,
+// reading the file backwards in chunks. It allows the newest entries of an
+// append-layout file to be read without reading the whole history.
+type ReverseScanner struct {
+	file    *os.File
+	offset  int64    // bytes before this offset have not been read yet
+	partial []byte   // start of the earliest line read so far
+	lines   []string // complete lines read but not yet returned
+	line    string
+	started bool
+	err     error
+}
+
+// NewReverseScanner creates a ReverseScanner for an open file
+func NewReverseScanner(file *os.File) (*ReverseScanner, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat '%s': %w", file.Name(), err)
+	}
+	return &ReverseScanner{file: file, offset: info.Size()}, nil
+}
+
+// Scan advances to the previous line, returning false at the start of the file
+func (s *ReverseScanner) Scan() bool {
+	for len(s.lines) == 0 {
+		if s.err != nil {
+			return false
+		}
+		if s.offset == 0 {
+			if s.partial == nil {
+				return false
+			}
+			s.lines = []string{string(s.partial)}
+			s.partial = nil
+			break
+		}
+		s.readChunk()
+	}
+
+	s.line = s.lines[len(s.lines)-1]
+	s.lines = s.lines[:len(s.lines)-1]
+	return true
+}
+
+// readChunk reads the previous chunk of the file and splits off complete lines
+func (s *ReverseScanner) readChunk() {
+	size := int64(reverseChunkSize)
+	if s.offset < size {
+		size = s.offset
+	}
+	s.offset -= size
+
+	chunk := make([]byte, size, int(size)+len(s.partial))
+	if _, err := s.file.ReadAt(chunk, s.offset); err != nil && err != io.EOF {
+		s.err = fmt.Errorf("failed to read '%s': %w", s.file.Name(), err)
+		return
+	}
+	data := append(chunk, s.partial...)
+
+	if !s.started {
+		// A trailing newline ends the last line rather than starting a new one
+		s.started = true
+		data = bytes.TrimSuffix(data, []byte("\n"))
+	}
+
+	parts := bytes.Split(data, []byte("\n"))
+	s.partial = parts[0]
+	for _, part := range parts[1:] {
+		s.lines = append(s.lines, string(part))
+	}
+}
+
+// Text returns the current line
+func (s *ReverseScanner) Text() string {
+	return s.line
+}
+
+// Err returns the first read error encountered
+func (s *ReverseScanner) Err() error {
+	return s.err
+}
+
+// RecentJournal returns journal entries at or after since, newest first.
+// Append-layout files are read backwards and only back to since.
+func (m *Manager) RecentJournal(format TimestampFormat, since time.Time) ([]Entry, error) {
+	return recentEntries(m.JournalPath, format, SourceJournal, since)
+}
+
+// RecentArchive returns archive entries at or after since, newest first.
+// Append-layout files are read backwards and only back to since.
+func (m *Manager) RecentArchive(format TimestampFormat, since time.Time) ([]Entry, error) {
+	return recentEntries(m.ArchivePath, format, SourceArchive, since)
+}
+
+// recentEntries reads the entries of a file at or after since, newest first
+func recentEntries(path string, format TimestampFormat, source string, since time.Time) ([]Entry, error) {
+	layout, err := DetectLayout(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if layout != LayoutAppend {
+		entries, err := readEntries(path, format, source)
+		if err != nil {
+			return nil, err
+		}
+		return recentOf(entries, since), nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s file '%s': %w", source, path, err)
+	}
+	defer file.Close()
+
+	scanner, err := NewReverseScanner(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reading backwards, details are seen before the entry they belong to, so
+	// lines are collected back to the first entry older than since and then
+	// grouped in file order as ParseEntries does. An entry always starts at a
+	// timestamped line that is not indented.
+	var reversed []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if ts, rest, ok := format.SplitTimestamp(line); ok && !isIndented(rest) && ts.Before(since) {
+			break
+		}
+		reversed = append(reversed, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s file '%s': %w", source, path, err)
+	}
+
+	parser := newEntryParser(format, source)
+	for i := len(reversed) - 1; i >= 0; i-- {
+		parser.add(reversed[i], len(reversed)-i)
+	}
+	// Line numbers count from the first line read, not the top of the file
+	for i := range parser.entries {
+		parser.entries[i].LineNumber, parser.entries[i].EndLine = 0, 0
+	}
+	return recentOf(parser.entries, since), nil
+}
+
+// recentOf returns the entries at or after since, newest first. Entries with
+// the same timestamp keep their file order, as in the prepend layout.
+func recentOf(entries []Entry, since time.Time) []Entry {
+	var recent []Entry
+	for _, entry := range entries {
+		if !entry.Timestamp.Before(since) {
+			recent = append(recent, entry)
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].Timestamp.After(recent[j].Timestamp)
+	})
+	return recent
+}
@@ -0,0 +1,294 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/utils"
+)
+
+func TestAppendLayout(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "journal-append-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	jm := NewManager(filepath.Join(tmpDir, "todo.md"))
+	jm.Layout = LayoutAppend
+
+	if err := jm.WriteToJournal([]string{"[2024-03-04 10:00:00 UTC] - [W] first", "  - detail"}); err != nil {
+		t.Fatalf("WriteToJournal failed: %v", err)
+	}
+	if err := jm.WriteToJournal([]string{"[2024-03-05 10:00:00 UTC] - [W] second"}); err != nil {
+		t.Fatalf("WriteToJournal failed: %v", err)
+	}
+
+	content, err := os.ReadFile(jm.JournalPath)
+	if err != nil {
+		t.Fatalf("Failed to read journal: %v", err)
+	}
+	want := AppendLayoutMarker + "\n[2024-03-04 10:00:00 UTC] - [W] first\n  - detail\n[2024-03-05 10:00:00 UTC] - [W] second\n"
+	if string(content) != want {
+		t.Errorf("Journal content = %q, want %q", content, want)
+	}
+
+	if layout, err := DetectLayout(jm.JournalPath); err != nil || layout != LayoutAppend {
+		t.Errorf("DetectLayout() = %q, %v, want %q", layout, err, LayoutAppend)
+	}
+
+	// The file keeps its layout even if the manager asks for prepend
+	prepend := NewManager(filepath.Join(tmpDir, "todo.md"))
+	if err := prepend.WriteToJournal([]string{"[2024-03-06 10:00:00 UTC] - [W] third"}); err != nil {
+		t.Fatalf("WriteToJournal failed: %v", err)
+	}
+	content, _ = os.ReadFile(jm.JournalPath)
+	if !strings.HasSuffix(string(content), "third\n") {
+		t.Errorf("Append-layout file should stay append-only:\n%s", content)
+	}
+
+	entries, err := jm.ReadJournal(DefaultTimestampFormat())
+	if err != nil {
+		t.Fatalf("ReadJournal failed: %v", err)
+	}
+	if len(entries) != 3 || len(entries[0].Details) != 1 {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+}
+
+func TestConvertToAppendLayout(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "journal-convert-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	jm := NewManager(filepath.Join(tmpDir, "todo.md"))
+	archive := `[2024-03-05 10:00:00 UTC] - [x] newer <!-- section: Work -->
+[2024-03-05 10:00:00 UTC]   - newer detail
+[2024-03-04 10:00:00 UTC] - [x] older
+`
+	if err := os.WriteFile(jm.ArchivePath, []byte(archive), 0644); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+
+	jm.Layout = LayoutAppend
+	if err := jm.WriteToArchive([]string{"[2024-03-06 10:00:00 UTC] - [x] newest"}); err != nil {
+		t.Fatalf("WriteToArchive failed: %v", err)
+	}
+
+	content, err := os.ReadFile(jm.ArchivePath)
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+	want := AppendLayoutMarker + `
+[2024-03-04 10:00:00 UTC] - [x] older
+[2024-03-05 10:00:00 UTC] - [x] newer <!-- section: Work -->
+[2024-03-05 10:00:00 UTC]   - newer detail
+[2024-03-06 10:00:00 UTC] - [x] newest
+`
+	if string(content) != want {
+		t.Errorf("Converted archive = %q, want %q", content, want)
+	}
+
+	// Converting again is a no-op
+	if err := ConvertToAppendLayout(jm.ArchivePath, DefaultTimestampFormat(), SourceArchive); err != nil {
+		t.Fatalf("ConvertToAppendLayout failed: %v", err)
+	}
+	again, _ := os.ReadFile(jm.ArchivePath)
+	if string(again) != want {
+		t.Errorf("Second conversion changed the file:\n%s", again)
+	}
+}
+
+func TestReverseScanner(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "reverse-scanner-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Lines spanning several chunks, with and without a trailing newline
+	var lines []string
+	for i := 0; i < 5000; i++ {
+		lines = append(lines, fmt.Sprintf("line %d %s", i, strings.Repeat("x", i%40)))
+	}
+
+	for _, trailing := range []string{"\n", ""} {
+		path := filepath.Join(tmpDir, "lines.md")
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+trailing), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("Failed to open file: %v", err)
+		}
+		scanner, err := NewReverseScanner(file)
+		if err != nil {
+			t.Fatalf("NewReverseScanner failed: %v", err)
+		}
+
+		var got []string
+		for scanner.Scan() {
+			got = append(got, scanner.Text())
+		}
+		file.Close()
+		if err := scanner.Err(); err != nil {
+			t.Fatalf("ReverseScanner error: %v", err)
+		}
+
+		if len(got) != len(lines) {
+			t.Fatalf("Got %d lines, want %d (trailing %q)", len(got), len(lines), trailing)
+		}
+		for i := range lines {
+			if got[len(got)-1-i] != lines[i] {
+				t.Fatalf("Line %d = %q, want %q", i, got[len(got)-1-i], lines[i])
+			}
+		}
+	}
+}
+
+func TestRecentEntries(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "journal-recent-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	since := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+	batches := [][]string{
+		{"[2024-03-04 10:00:00 UTC] - [W] old"},
+		{"[2024-03-05 10:00:00 UTC] - [W] recent", "  - recent detail", "[2024-03-05 10:00:00 UTC]   - [B] sub"},
+		{"[2024-03-06 10:00:00 UTC] - [X] newest"},
+	}
+
+	for _, layout := range []string{LayoutPrepend, LayoutAppend} {
+		t.Run(layout, func(t *testing.T) {
+			jm := NewManager(filepath.Join(tmpDir, layout+".md"))
+			jm.Layout = layout
+			for _, batch := range batches {
+				if err := jm.WriteToJournal(batch); err != nil {
+					t.Fatalf("WriteToJournal failed: %v", err)
+				}
+			}
+
+			entries, err := jm.RecentJournal(DefaultTimestampFormat(), since)
+			if err != nil {
+				t.Fatalf("RecentJournal failed: %v", err)
+			}
+			var titles []string
+			for _, entry := range entries {
+				titles = append(titles, strings.TrimSpace(entry.Line))
+			}
+			if strings.Join(titles, "|") != "- [X] newest|- [W] recent|- [B] sub" {
+				t.Errorf("RecentJournal() = %v", titles)
+			}
+			if len(entries) > 1 && (len(entries[1].Details) != 1 || entries[1].Details[0] != "  - recent detail") {
+				t.Errorf("Details not attached: %+v", entries[1])
+			}
+		})
+	}
+}
+
+func TestArchiveDetailsAgreeAcrossReaders(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "journal-details-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// The indented line has a timestamp of its own, so it is not a detail of
+	// the task above it
+	batches := [][]string{
+		{"[2024-03-05 10:00:00 UTC] - [x] parent", "[2024-03-05 10:00:00 UTC]   - parent detail"},
+		{"[2024-03-06 10:00:00 UTC]   - [x] subtask archived alone"},
+		{"[2024-03-07 10:00:00 UTC] - [x] newest"},
+	}
+	want := "- [x] newest|  - [x] subtask archived alone|- [x] parent [  - parent detail]"
+	describe := func(entries []Entry) string {
+		var parts []string
+		for _, entry := range entries {
+			part := entry.Line
+			if len(entry.Details) > 0 {
+				part += " [" + strings.Join(entry.Details, ",") + "]"
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, "|")
+	}
+
+	for _, layout := range []string{LayoutPrepend, LayoutAppend} {
+		t.Run(layout, func(t *testing.T) {
+			jm := NewManager(filepath.Join(tmpDir, layout+".md"))
+			jm.Layout = layout
+			for _, batch := range batches {
+				if err := jm.WriteToArchive(batch); err != nil {
+					t.Fatalf("WriteToArchive failed: %v", err)
+				}
+			}
+
+			recent, err := jm.RecentArchive(DefaultTimestampFormat(), time.Time{})
+			if err != nil {
+				t.Fatalf("RecentArchive failed: %v", err)
+			}
+			if got := describe(recent); got != want {
+				t.Errorf("RecentArchive() = %q, want %q", got, want)
+			}
+
+			all, err := jm.ReadArchive(DefaultTimestampFormat())
+			if err != nil {
+				t.Fatalf("ReadArchive failed: %v", err)
+			}
+			if got := describe(recentOf(all, time.Time{})); got != want {
+				t.Errorf("ReadArchive() = %q, want %q", got, want)
+			}
+		})
+	}
+
+	// Converting a prepend-layout archive keeps the subtask apart from the parent
+	jm := NewManager(filepath.Join(tmpDir, LayoutPrepend+".md"))
+	if err := ConvertToAppendLayout(jm.ArchivePath, DefaultTimestampFormat(), SourceArchive); err != nil {
+		t.Fatalf("ConvertToAppendLayout failed: %v", err)
+	}
+	content, _ := os.ReadFile(jm.ArchivePath)
+	wantContent := AppendLayoutMarker + `
+[2024-03-05 10:00:00 UTC] - [x] parent
+[2024-03-05 10:00:00 UTC]   - parent detail
+[2024-03-06 10:00:00 UTC]   - [x] subtask archived alone
+[2024-03-07 10:00:00 UTC] - [x] newest
+`
+	if string(content) != wantContent {
+		t.Errorf("Converted archive = %q, want %q", content, wantContent)
+	}
+}
+
+func TestReadEntriesBeyondMaxFileSize(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "journal-large-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	jm := NewManager(filepath.Join(tmpDir, "todo.md"))
+	line := "[2024-03-04 10:00:00 UTC] - [W] " + strings.Repeat("history ", 12) + "\n"
+	count := utils.MaxFileSize/len(line) + 100
+	if err := os.WriteFile(jm.JournalPath, []byte(strings.Repeat(line, count)), 0644); err != nil {
+		t.Fatalf("Failed to write journal: %v", err)
+	}
+
+	if err := jm.WriteToJournal([]string{"[2024-03-05 10:00:00 UTC] - [W] new"}); err != nil {
+		t.Fatalf("WriteToJournal on large journal failed: %v", err)
+	}
+	entries, err := jm.ReadJournal(DefaultTimestampFormat())
+	if err != nil {
+		t.Fatalf("ReadJournal on large journal failed: %v", err)
+	}
+	if len(entries) != count+1 {
+		t.Errorf("Got %d entries, want %d", len(entries), count+1)
+	}
+}
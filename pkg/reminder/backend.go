@@ -0,0 +1,111 @@
+package reminder
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Backend names
+const (
+	BackendReminders = "reminders" // macOS Reminders.app via AppleScript
+	DefaultBackend   = BackendReminders
+)
+
+// Reminder is a reminder as stored by a backend
+type Reminder struct {
+	ID        string // assigned by the backend when the reminder is added
+	Key       string // stable task key used by Sync; empty for reminders created elsewhere
+	Title     string
+	Notes     string
+	Due       time.Time // zero for no due date
+	Priority  int       // 0 none, 1 high, 5 medium, 9 low (the iCalendar scale)
+	Flagged   bool
+	Completed bool
+	List      string // list the reminder is in; empty for the backend's default list
+	Line      int    // line of the task in the todo file, for messages; 0 when unknown
+
+	// NotesHash is the hash of the notes as last written by Sync, stored by
+	// the backend so that Sync can tell generated notes from edited ones
+	NotesHash string
+}
+
+// Priorities on the iCalendar scale used by Reminder.Priority
+const (
+	PriorityNone   = 0
+	PriorityHigh   = 1
+	PriorityMedium = 5
+	PriorityLow    = 9
+)
+
+// Backend is a reminder store that tasks can be synced to
+type Backend interface {
+	// List returns all reminders in the backend's list
+	List() ([]Reminder, error)
+	// Add creates a reminder and returns its ID
+	Add(r Reminder) (string, error)
+	// Update replaces the title, notes, notes hash, due date, priority, flag, key and list of the reminder with r.ID
+	Update(r Reminder) error
+	// Complete marks the reminder as completed
+	Complete(id string) error
+	// Delete removes the reminder
+	Delete(id string) error
+}
+
+// Options configures a backend
+type Options struct {
+	ListName  string
+	DuePolicy DuePolicy
+
+	// Lists are the other lists that reminders are synced to besides ListName
+	Lists []string
+
+	// CalDAV collection and credentials
+	URL      string
+	Username string
+	Password string
+}
+
+// Factory creates a backend from options
+type Factory func(opts Options) (Backend, error)
+
+// backends holds the registered backend factories by name
+var backends = map[string]Factory{
+	BackendReminders: func(opts Options) (Backend, error) {
+		s := NewService(opts.ListName)
+		s.DuePolicy = opts.DuePolicy
+		s.Lists = opts.Lists
+		return s, nil
+	},
+	BackendCalDAV: func(opts Options) (Backend, error) {
+		return NewCalDAVBackend(opts.URL, opts.Username, opts.Password)
+	},
+}
+
+// Register makes a backend available under name, replacing any existing one
+func Register(name string, factory Factory) {
+	backends[name] = factory
+}
+
+// BackendNames returns the names of the registered backends, sorted
+func BackendNames() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewBackend creates the backend registered under name.
+// An empty name selects DefaultBackend.
+func NewBackend(name string, opts Options) (Backend, error) {
+	if name == "" {
+		name = DefaultBackend
+	}
+	factory, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown reminder backend '%s' (available: %v)", name, BackendNames())
+	}
+	return factory(opts)
+}
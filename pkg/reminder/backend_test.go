@@ -0,0 +1,75 @@
+package reminder
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewBackend(t *testing.T) {
+	backend, err := NewBackend("", Options{ListName: "Todo"})
+	if err != nil {
+		t.Fatalf("NewBackend() error = %v", err)
+	}
+	service, ok := backend.(*Service)
+	if !ok || service.ListName != "Todo" {
+		t.Errorf("NewBackend(\"\") = %#v, want Reminders.app service for 'Todo'", backend)
+	}
+
+	if _, err := NewBackend("unknown", Options{}); err == nil || !strings.Contains(err.Error(), BackendReminders) {
+		t.Errorf("NewBackend(unknown) error = %v, want error listing available backends", err)
+	}
+
+	memory := NewMemoryBackend()
+	Register("test-memory", func(opts Options) (Backend, error) { return memory, nil })
+	defer delete(backends, "test-memory")
+
+	backend, err = NewBackend("test-memory", Options{})
+	if err != nil || backend != Backend(memory) {
+		t.Errorf("NewBackend(test-memory) = %v, %v, want registered backend", backend, err)
+	}
+}
+
+func TestMemoryBackend(t *testing.T) {
+	backend := NewMemoryBackend()
+	due := time.Date(2024, 3, 5, 16, 0, 0, 0, time.UTC)
+
+	first, _ := backend.Add(Reminder{Title: "First"})
+	second, _ := backend.Add(Reminder{Title: "Second"})
+	if first == second {
+		t.Fatalf("Add() returned duplicate ID %q", first)
+	}
+
+	if err := backend.Update(Reminder{ID: first, Title: "First, renamed", Notes: "note", Due: due}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := backend.Complete(second); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	reminders, _ := backend.List()
+	want := []Reminder{
+		{ID: first, Title: "First, renamed", Notes: "note", Due: due},
+		{ID: second, Title: "Second", Completed: true},
+	}
+	if len(reminders) != len(want) || reminders[0] != want[0] || reminders[1] != want[1] {
+		t.Errorf("List() = %+v, want %+v", reminders, want)
+	}
+
+	if err := backend.Delete(first); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if reminders, _ := backend.List(); len(reminders) != 1 || reminders[0].ID != second {
+		t.Errorf("List() after Delete = %+v", reminders)
+	}
+
+	for name, err := range map[string]error{
+		"Update":   backend.Update(Reminder{ID: "missing"}),
+		"Complete": backend.Complete("missing"),
+		"Delete":   backend.Delete("missing"),
+	} {
+		if err == nil {
+			t.Errorf("%s() on missing reminder should fail", name)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package reminder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operation is the kind of change a sync makes to a backend
+type Operation int
+
+const (
+	OpAdd Operation = iota
+	OpUpdate
+	OpComplete
+	OpDelete
+)
+
+// String returns the verb used for the operation in messages
+func (o Operation) String() string {
+	switch o {
+	case OpAdd:
+		return "add"
+	case OpUpdate:
+		return "update"
+	case OpComplete:
+		return "complete"
+	case OpDelete:
+		return "remove"
+	default:
+		return "change"
+	}
+}
+
+// Change is a single change to a backend. Complete and delete changes only
+// use the ID and Title of the reminder.
+type Change struct {
+	Op       Operation
+	Reminder Reminder
+}
+
+// ChangeError is a change that failed
+type ChangeError struct {
+	Change Change
+	Err    error
+}
+
+// Error describes the failed change, starting with the task's line number when known
+func (e *ChangeError) Error() string {
+	msg := fmt.Sprintf("failed to %s reminder '%s': %v", e.Change.Op, e.Change.Reminder.Title, e.Err)
+	if e.Change.Reminder.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Change.Reminder.Line, msg)
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ChangeError) Unwrap() error {
+	return e.Err
+}
+
+// ChangeErrors collects the changes of a sync that failed
+type ChangeErrors []*ChangeError
+
+// Error lists the failed changes, one per line
+func (e ChangeErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d reminder changes failed:\n%s", len(e), strings.Join(messages, "\n"))
+}
+
+// BatchBackend is a backend that applies many changes in one call, for
+// backends where every call is expensive
+type BatchBackend interface {
+	Backend
+	// ApplyBatch applies changes in order and returns the error of each
+	// change, nil for changes that succeeded. The error return is for
+	// failures that kept the batch from running at all.
+	ApplyBatch(changes []Change) ([]error, error)
+}
+
+// Apply applies changes to the backend, in a single batch when the backend is
+// a BatchBackend. A failed change does not stop the others; the failures are
+// returned as ChangeErrors. It returns the number of changes that succeeded
+// for each operation.
+func Apply(backend Backend, changes []Change) (map[Operation]int, error) {
+	var errs []error
+	if batch, ok := backend.(BatchBackend); ok && len(changes) > 0 {
+		var err error
+		errs, err = batch.ApplyBatch(changes)
+		if err != nil {
+			return nil, err
+		}
+		if len(errs) != len(changes) {
+			return nil, fmt.Errorf("batch returned %d results for %d changes", len(errs), len(changes))
+		}
+	} else {
+		errs = make([]error, len(changes))
+		for i, change := range changes {
+			errs[i] = applyChange(backend, change)
+		}
+	}
+
+	applied := make(map[Operation]int)
+	var failed ChangeErrors
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, &ChangeError{Change: changes[i], Err: err})
+			continue
+		}
+		applied[changes[i].Op]++
+	}
+	if len(failed) > 0 {
+		return applied, failed
+	}
+	return applied, nil
+}
+
+// applyChange applies a single change with the Backend methods
+func applyChange(backend Backend, change Change) error {
+	switch change.Op {
+	case OpAdd:
+		_, err := backend.Add(change.Reminder)
+		return err
+	case OpUpdate:
+		return backend.Update(change.Reminder)
+	case OpComplete:
+		return backend.Complete(change.Reminder.ID)
+	case OpDelete:
+		return backend.Delete(change.Reminder.ID)
+	default:
+		return fmt.Errorf("unknown operation %d", change.Op)
+	}
+}
@@ -0,0 +1,113 @@
+package reminder
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApply(t *testing.T) {
+	backend := NewMemoryBackend()
+	existing, _ := backend.Add(Reminder{Key: "k1", Title: "Existing"})
+
+	applied, err := Apply(backend, []Change{
+		{Op: OpAdd, Reminder: Reminder{Key: "k2", Title: "New", Line: 3}},
+		{Op: OpUpdate, Reminder: Reminder{ID: "missing", Title: "Gone", Line: 7}},
+		{Op: OpComplete, Reminder: Reminder{ID: existing, Title: "Existing"}},
+		{Op: OpDelete, Reminder: Reminder{ID: "also-missing", Title: "Old"}},
+	})
+
+	var changeErrs ChangeErrors
+	if !errors.As(err, &changeErrs) || len(changeErrs) != 2 {
+		t.Fatalf("Apply() error = %v, want two change errors", err)
+	}
+	if got := changeErrs[0].Error(); !strings.HasPrefix(got, "line 7: failed to update reminder 'Gone'") {
+		t.Errorf("first change error = %q, want it to start with the line number", got)
+	}
+	if got := changeErrs[1].Error(); !strings.HasPrefix(got, "failed to remove reminder 'Old'") {
+		t.Errorf("second change error = %q", got)
+	}
+	if applied[OpAdd] != 1 || applied[OpComplete] != 1 || applied[OpUpdate] != 0 {
+		t.Errorf("Apply() applied = %v", applied)
+	}
+	if reminders, _ := backend.List(); len(reminders) != 2 || !reminders[0].Completed {
+		t.Errorf("reminders after Apply() = %+v", reminders)
+	}
+}
+
+func TestServiceApplyBatch(t *testing.T) {
+	originalExecCommand := ExecCommand
+	defer func() { ExecCommand = originalExecCommand }()
+
+	var scripts []string
+	ExecCommand = func(command string, args ...string) *exec.Cmd {
+		scripts = append(scripts, args[1])
+		cmd := helperCommand(command, args...)
+		cmd.Env = append(cmd.Env, "HELPER_OUTPUT=ok\x1fnew-id\x1e\nerror\x1fCan't get reminder id \"gone\".\x1e\nok\x1f\x1e")
+		return cmd
+	}
+
+	service := NewService("Todo")
+	due := time.Date(2024, 3, 5, 16, 0, 0, 0, time.Local)
+	desired := []Reminder{
+		{Key: "k1", Title: `Call "Bob"`, Due: due, Priority: PriorityHigh, Line: 2},
+		{Key: "k2", Title: "Renamed", Line: 5},
+	}
+	existing := []Reminder{
+		{ID: "gone", Key: "k2", Title: "Old name"},
+		{ID: "stale", Key: "k3", Title: "No longer active"},
+	}
+
+	result, err := Reconcile(service, existing, desired)
+	if len(scripts) != 1 {
+		t.Fatalf("Reconcile() ran osascript %d times, want once", len(scripts))
+	}
+
+	var changeErrs ChangeErrors
+	if !errors.As(err, &changeErrs) || len(changeErrs) != 1 {
+		t.Fatalf("Reconcile() error = %v, want one change error", err)
+	}
+	if got := changeErrs[0].Error(); !strings.Contains(got, "line 5: failed to update reminder 'Renamed'") || !strings.Contains(got, `Can't get reminder id "gone"`) {
+		t.Errorf("change error = %q", got)
+	}
+	if want := (SyncResult{Added: 1, Removed: 1}); *result != want {
+		t.Errorf("Reconcile() = %+v, want %+v", *result, want)
+	}
+
+	script := scripts[0]
+	for _, want := range []string{
+		"set dueDate0 to current date",
+		`repeat with listName in {"Todo"}`,
+		"if not (exists list listName) then make new list with properties {name:listName}",
+		`tell list "Todo"`,
+		`make new reminder with properties {name:"Call \"Bob\"", body:"taskmasterra-key: k1 da39a3ee5e6b", priority:1, flagged:false}`,
+		"set due date of r to dueDate0",
+		`set r to reminder id "gone"`,
+		"set due date of r to missing value",
+		`delete (reminder id "stale")`,
+		"on error errMsg",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("batch script missing %q:\n%s", want, script)
+		}
+	}
+	// Dates are set up before the tell block
+	if strings.Index(script, "set dueDate0") > strings.Index(script, `tell application "Reminders"`) {
+		t.Errorf("due dates should be built before telling Reminders:\n%s", script)
+	}
+}
+
+func TestParseBatchResults(t *testing.T) {
+	errs, err := parseBatchResults("ok\x1fid\x1eerror\x1fboom\x1e", 2)
+	if err != nil || len(errs) != 2 || errs[0] != nil || errs[1] == nil || !strings.Contains(errs[1].Error(), "boom") {
+		t.Errorf("parseBatchResults() = %v, %v", errs, err)
+	}
+	if _, err := parseBatchResults("ok\x1f\x1e", 2); err == nil {
+		t.Error("parseBatchResults() should fail when results are missing")
+	}
+	if _, err := parseBatchResults("maybe\x1f\x1e", 1); err == nil {
+		t.Error("parseBatchResults() should fail on an unknown status")
+	}
+}
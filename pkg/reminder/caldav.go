@@ -0,0 +1,310 @@
+package reminder
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/ical"
+)
+
+// BackendCalDAV syncs reminders as VTODO items to a CalDAV calendar collection
+const BackendCalDAV = "caldav"
+
+// uidSuffix marks the UIDs of VTODOs created by a sync; the task key precedes it
+const uidSuffix = "@taskmasterra"
+
+// notesHashProperty is the VTODO property that holds Reminder.NotesHash
+const notesHashProperty = "X-TASKMASTERRA-NOTES-HASH"
+
+// calendarQuery asks a CalDAV server for every VTODO in a collection
+const calendarQuery = `<?xml version="1.0" encoding="utf-8"?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/><C:calendar-data/></D:prop>
+  <C:filter><C:comp-filter name="VCALENDAR"><C:comp-filter name="VTODO"/></C:comp-filter></C:filter>
+</C:calendar-query>`
+
+// CalDAVBackend stores reminders as VTODO resources in a CalDAV calendar collection.
+// It implements Backend; reminder IDs are the resource URLs.
+type CalDAVBackend struct {
+	CollectionURL string
+	Username      string
+	Password      string
+	Client        *http.Client
+}
+
+// NewCalDAVBackend creates a backend for the calendar collection at collectionURL
+func NewCalDAVBackend(collectionURL, username, password string) (*CalDAVBackend, error) {
+	parsed, err := url.Parse(collectionURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid CalDAV collection URL '%s'", collectionURL)
+	}
+	if !strings.HasSuffix(parsed.Path, "/") {
+		parsed.Path += "/"
+	}
+
+	return &CalDAVBackend{
+		CollectionURL: parsed.String(),
+		Username:      username,
+		Password:      password,
+		Client:        &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// multistatus is the subset of a WebDAV multistatus response read by List
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Status string `xml:"status"`
+			Prop   struct {
+				CalendarData string `xml:"calendar-data"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// List returns the VTODOs in the collection
+func (c *CalDAVBackend) List() ([]Reminder, error) {
+	resp, err := c.do("REPORT", c.CollectionURL, strings.NewReader(calendarQuery), map[string]string{
+		"Content-Type": "application/xml; charset=utf-8",
+		"Depth":        "1",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VTODOs in '%s': %w", c.CollectionURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("failed to list VTODOs in '%s': %s", c.CollectionURL, resp.Status)
+	}
+
+	var result multistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse CalDAV response from '%s': %w", c.CollectionURL, err)
+	}
+
+	var reminders []Reminder
+	for _, response := range result.Responses {
+		for _, propstat := range response.Propstat {
+			if propstat.Prop.CalendarData == "" || (propstat.Status != "" && !strings.Contains(propstat.Status, " 200 ")) {
+				continue
+			}
+			todos, err := ical.Parse(propstat.Prop.CalendarData)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse VTODO at '%s': %w", response.Href, err)
+			}
+			resource, err := c.resolve(response.Href)
+			if err != nil {
+				return nil, err
+			}
+			for _, todo := range todos {
+				reminders = append(reminders, todoToReminder(resource, todo))
+			}
+		}
+	}
+
+	return reminders, nil
+}
+
+// Add creates a VTODO resource and returns its URL
+func (c *CalDAVBackend) Add(r Reminder) (string, error) {
+	uid := uidFor(r)
+	resource, err := c.resolve(url.PathEscape(uid) + ".ics")
+	if err != nil {
+		return "", err
+	}
+	todo := applyReminder(ical.Todo{UID: uid, Completed: r.Completed}, r)
+	if err := c.put(resource, todo, map[string]string{"If-None-Match": "*"}); err != nil {
+		return "", fmt.Errorf("failed to add VTODO '%s': %w", r.Title, err)
+	}
+	return resource, nil
+}
+
+// Update replaces the fields of the VTODO at r.ID that taskmasterra owns,
+// keeping everything else, such as alarms, recurrence and other categories
+func (c *CalDAVBackend) Update(r Reminder) error {
+	current, err := c.get(r.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update VTODO '%s': %w", r.Title, err)
+	}
+	if err := c.put(r.ID, applyReminder(current, r), nil); err != nil {
+		return fmt.Errorf("failed to update VTODO '%s': %w", r.Title, err)
+	}
+	return nil
+}
+
+// Complete marks the VTODO at id as completed
+func (c *CalDAVBackend) Complete(id string) error {
+	current, err := c.get(id)
+	if err != nil {
+		return fmt.Errorf("failed to complete VTODO '%s': %w", id, err)
+	}
+	current.Completed = true
+	if err := c.put(id, current, nil); err != nil {
+		return fmt.Errorf("failed to complete VTODO '%s': %w", id, err)
+	}
+	return nil
+}
+
+// Delete removes the VTODO resource at id
+func (c *CalDAVBackend) Delete(id string) error {
+	resp, err := c.do(http.MethodDelete, id, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete VTODO '%s': %w", id, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete VTODO '%s': %s", id, resp.Status)
+	}
+	return nil
+}
+
+// get fetches and parses the VTODO at resource
+func (c *CalDAVBackend) get(resource string) (ical.Todo, error) {
+	resp, err := c.do(http.MethodGet, resource, nil, nil)
+	if err != nil {
+		return ical.Todo{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ical.Todo{}, fmt.Errorf("GET '%s': %s", resource, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ical.Todo{}, fmt.Errorf("failed to read '%s': %w", resource, err)
+	}
+	todos, err := ical.Parse(string(data))
+	if err != nil {
+		return ical.Todo{}, fmt.Errorf("failed to parse '%s': %w", resource, err)
+	}
+	if len(todos) == 0 {
+		return ical.Todo{}, fmt.Errorf("'%s' does not contain a VTODO", resource)
+	}
+	return todos[0], nil
+}
+
+// put writes todo to resource
+func (c *CalDAVBackend) put(resource string, todo ical.Todo, headers map[string]string) error {
+	body := ical.Encode([]ical.Todo{todo}, time.Now())
+
+	allHeaders := map[string]string{"Content-Type": "text/calendar; charset=utf-8"}
+	for key, value := range headers {
+		allHeaders[key] = value
+	}
+
+	resp, err := c.do(http.MethodPut, resource, bytes.NewBufferString(body), allHeaders)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("PUT '%s': %s", resource, resp.Status)
+	}
+	return nil
+}
+
+// do sends an authenticated request
+func (c *CalDAVBackend) do(method, target string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// resolve returns the absolute URL of a reference relative to the collection
+func (c *CalDAVBackend) resolve(ref string) (string, error) {
+	base, err := url.Parse(c.CollectionURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid CalDAV collection URL '%s': %w", c.CollectionURL, err)
+	}
+	target, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid CalDAV resource '%s': %w", ref, err)
+	}
+	return base.ResolveReference(target).String(), nil
+}
+
+// uidFor returns the UID for a new VTODO. Synced reminders carry their task key.
+func uidFor(r Reminder) string {
+	if r.Key != "" {
+		return r.Key + uidSuffix
+	}
+	return fmt.Sprintf("%d%s.local", time.Now().UnixNano(), uidSuffix)
+}
+
+// applyReminder returns todo with the summary, description, notes hash, due
+// date, priority, flag and list set from r. Its other properties are kept.
+func applyReminder(todo ical.Todo, r Reminder) ical.Todo {
+	todo.AllDay = todo.AllDay && todo.Due.Equal(r.Due)
+	todo.Summary = r.Title
+	todo.Description = r.Notes
+	todo.Due = r.Due
+	todo.Priority = r.Priority
+	todo.Flagged = r.Flagged
+
+	// A collection has no lists; the list is kept as the VTODO's first category
+	var categories []string
+	if r.List != "" {
+		categories = append(categories, r.List)
+	}
+	if len(todo.Categories) > 1 {
+		categories = append(categories, todo.Categories[1:]...)
+	}
+	todo.Categories = categories
+
+	var extra []string
+	for _, line := range todo.Extra {
+		if !strings.HasPrefix(line, notesHashProperty+":") {
+			extra = append(extra, line)
+		}
+	}
+	if r.NotesHash != "" {
+		extra = append([]string{notesHashProperty + ":" + r.NotesHash}, extra...)
+	}
+	todo.Extra = extra
+	return todo
+}
+
+// todoToReminder converts a VTODO stored at resource to a reminder
+func todoToReminder(resource string, todo ical.Todo) Reminder {
+	r := Reminder{
+		ID:        resource,
+		Title:     todo.Summary,
+		Notes:     todo.Description,
+		Due:       todo.Due,
+		Priority:  todo.Priority,
+		Flagged:   todo.Flagged,
+		Completed: todo.Completed,
+	}
+	if strings.HasSuffix(todo.UID, uidSuffix) {
+		r.Key = strings.TrimSuffix(todo.UID, uidSuffix)
+	}
+	if len(todo.Categories) > 0 {
+		r.List = todo.Categories[0]
+	}
+	for _, line := range todo.Extra {
+		if strings.HasPrefix(line, notesHashProperty+":") {
+			r.NotesHash = strings.TrimPrefix(line, notesHashProperty+":")
+		}
+	}
+	return r
+}
@@ -0,0 +1,216 @@
+package reminder
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// calDAVStandIn is a minimal in-process CalDAV server holding one collection
+type calDAVStandIn struct {
+	mu        sync.Mutex
+	resources map[string]string // path -> calendar data
+}
+
+func newCalDAVStandIn(t *testing.T) (*calDAVStandIn, *httptest.Server) {
+	standIn := &calDAVStandIn{resources: make(map[string]string)}
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+	return standIn, server
+}
+
+func (s *calDAVStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case "REPORT":
+		if r.URL.Path != "/cal/tasks/" || r.Header.Get("Depth") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		paths := make([]string, 0, len(s.resources))
+		for path := range s.resources {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprint(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">`)
+		for _, path := range paths {
+			data := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s.resources[path])
+			fmt.Fprintf(w, `<d:response><d:href>%s</d:href><d:propstat><d:prop><cal:calendar-data>%s</cal:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`, path, data)
+		}
+		fmt.Fprint(w, `</d:multistatus>`)
+	case http.MethodPut:
+		if _, exists := s.resources[r.URL.Path]; exists && r.Header.Get("If-None-Match") == "*" {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		s.resources[r.URL.Path] = string(body)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet:
+		data, ok := s.resources[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, data)
+	case http.MethodDelete:
+		delete(s.resources, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestNewCalDAVBackend(t *testing.T) {
+	backend, err := NewCalDAVBackend("https://dav.example.com/cal/tasks", "", "")
+	if err != nil {
+		t.Fatalf("NewCalDAVBackend() error = %v", err)
+	}
+	if backend.CollectionURL != "https://dav.example.com/cal/tasks/" {
+		t.Errorf("CollectionURL = %q, want trailing slash", backend.CollectionURL)
+	}
+
+	for _, invalid := range []string{"", "ftp://example.com/cal", "not a url"} {
+		if _, err := NewCalDAVBackend(invalid, "", ""); err == nil {
+			t.Errorf("NewCalDAVBackend(%q) should fail", invalid)
+		}
+	}
+
+	if _, err := NewBackend(BackendCalDAV, Options{URL: "https://dav.example.com/cal/"}); err != nil {
+		t.Errorf("NewBackend(caldav) error = %v", err)
+	}
+}
+
+func TestCalDAVBackend(t *testing.T) {
+	standIn, server := newCalDAVStandIn(t)
+	backend, err := NewCalDAVBackend(server.URL+"/cal/tasks", "user", "secret")
+	if err != nil {
+		t.Fatalf("NewCalDAVBackend() error = %v", err)
+	}
+
+	due := time.Date(2024, 3, 5, 16, 0, 0, 0, time.UTC)
+	id, err := backend.Add(Reminder{Key: "abc12345", Title: "Call the bank", Notes: "Priority: High", Due: due, Priority: PriorityHigh})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if id != server.URL+"/cal/tasks/abc12345@taskmasterra.ics" {
+		t.Errorf("Add() id = %q", id)
+	}
+	if data := standIn.resources["/cal/tasks/abc12345@taskmasterra.ics"]; !strings.Contains(data, "UID:abc12345@taskmasterra") || !strings.Contains(data, "PRIORITY:1") {
+		t.Errorf("stored VTODO missing UID or priority:\n%s", data)
+	}
+
+	reminders, err := backend.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	want := Reminder{ID: id, Key: "abc12345", Title: "Call the bank", Notes: "Priority: High", Due: due, Priority: PriorityHigh}
+	if len(reminders) != 1 || reminders[0] != want {
+		t.Errorf("List() = %+v, want %+v", reminders, want)
+	}
+
+	if err := backend.Complete(id); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if err := backend.Update(Reminder{ID: id, Key: "abc12345", Title: "Call the bank today", List: "Errands"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	reminders, _ = backend.List()
+	if len(reminders) != 1 || reminders[0].Title != "Call the bank today" || !reminders[0].Completed || !reminders[0].Due.IsZero() {
+		t.Errorf("List() after Update = %+v, want renamed, still completed, no due date", reminders)
+	}
+	if reminders[0].List != "Errands" || !strings.Contains(standIn.resources["/cal/tasks/abc12345@taskmasterra.ics"], "CATEGORIES:Errands") {
+		t.Errorf("List() after Update = %+v, want the list kept as the category", reminders)
+	}
+
+	if err := backend.Delete(id); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if len(standIn.resources) != 0 {
+		t.Errorf("resources after Delete = %v", standIn.resources)
+	}
+
+	backend.Password = "wrong"
+	if _, err := backend.List(); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("List() with bad credentials error = %v, want 401", err)
+	}
+}
+
+func TestCalDAVBackendKeepsOtherProperties(t *testing.T) {
+	standIn, server := newCalDAVStandIn(t)
+	backend, _ := NewCalDAVBackend(server.URL+"/cal/tasks/", "user", "secret")
+
+	// Another client added an alarm, a recurrence and a category of its own
+	path := "/cal/tasks/abc12345@taskmasterra.ics"
+	standIn.resources[path] = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\n" +
+		"UID:abc12345@taskmasterra\r\nSUMMARY:Water plants\r\nDUE:20240305T160000Z\r\n" +
+		"DTSTART:20240305T160000Z\r\nRRULE:FREQ=WEEKLY\r\nCATEGORIES:Home,Garden\r\n" +
+		"X-APPLE-SORT-ORDER:42\r\nBEGIN:VALARM\r\nACTION:DISPLAY\r\nDESCRIPTION:Reminder\r\n" +
+		"TRIGGER:-PT15M\r\nEND:VALARM\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+	id := server.URL + path
+
+	due := time.Date(2024, 3, 6, 9, 0, 0, 0, time.UTC)
+	if err := backend.Update(Reminder{ID: id, Key: "abc12345", Title: "Water the plants", Due: due, List: "Chores"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := backend.Complete(id); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	data := standIn.resources[path]
+	for _, want := range []string{
+		"SUMMARY:Water the plants", "DUE:20240306T090000Z", "STATUS:COMPLETED",
+		"CATEGORIES:Chores,Garden", "DTSTART:20240305T160000Z", "RRULE:FREQ=WEEKLY",
+		"X-APPLE-SORT-ORDER:42", "BEGIN:VALARM\r\nACTION:DISPLAY\r\nDESCRIPTION:Reminder\r\nTRIGGER:-PT15M\r\nEND:VALARM",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("stored VTODO missing %q:\n%s", want, data)
+		}
+	}
+	if strings.Count(data, "DTSTART") != 1 || strings.Count(data, "DESCRIPTION:Reminder") != 1 {
+		t.Errorf("stored VTODO has a duplicate DTSTART or the alarm leaked into the task:\n%s", data)
+	}
+}
+
+func TestCalDAVBackendSync(t *testing.T) {
+	standIn, server := newCalDAVStandIn(t)
+	backend, _ := NewCalDAVBackend(server.URL+"/cal/tasks/", "user", "secret")
+
+	// A VTODO created by another client is never touched
+	standIn.resources["/cal/tasks/phone.ics"] = "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:phone-1\r\nSUMMARY:Buy milk\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+	desired := []Reminder{{Key: "k1", Title: "First"}, {Key: "k2", Title: "Second", Priority: PriorityLow}}
+	result, err := Sync(backend, desired)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result.Added != 2 {
+		t.Errorf("first Sync() = %+v, want 2 added", *result)
+	}
+
+	result, err = Sync(backend, desired[1:])
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if *result != (SyncResult{Removed: 1, Unchanged: 1}) {
+		t.Errorf("second Sync() = %+v, want 1 removed, 1 unchanged", *result)
+	}
+	if _, ok := standIn.resources["/cal/tasks/phone.ics"]; !ok {
+		t.Error("VTODO created by another client was removed")
+	}
+}
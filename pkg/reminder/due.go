@@ -0,0 +1,53 @@
+package reminder
+
+import (
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+// DuePolicy decides which reminders get a due date and when they are due
+type DuePolicy struct {
+	// Priorities lists the task priorities that are due today when the task
+	// has no date of its own
+	Priorities []task.Priority
+
+	// Hour and Minute are the time of day used for due dates without a time
+	Hour   int
+	Minute int
+
+	// IgnoreTaskDates stops a task's own org-mode date from setting its due date
+	IgnoreTaskDates bool
+}
+
+// DefaultDuePolicy returns the policy used before due dates were configurable:
+// Critical and High tasks are due today at 16:00.
+func DefaultDuePolicy() DuePolicy {
+	return DuePolicy{
+		Priorities: []task.Priority{task.PriorityCritical, task.PriorityHigh},
+		Hour:       16,
+	}
+}
+
+// Due returns the due date for a task, or the zero time for no due date.
+// A task's own date wins over its priority; whole-day dates and tasks due
+// today by priority are due at the policy's time of day.
+func (p DuePolicy) Due(t task.Task, now time.Time) time.Time {
+	if t.Date != nil && !p.IgnoreTaskDates {
+		if t.Date.HasTime {
+			return t.Date.Time
+		}
+		return p.At(t.Date.Time)
+	}
+	for _, priority := range p.Priorities {
+		if t.Priority == priority {
+			return p.At(now)
+		}
+	}
+	return time.Time{}
+}
+
+// At returns day at the policy's time of day
+func (p DuePolicy) At(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), p.Hour, p.Minute, 0, 0, day.Location())
+}
@@ -0,0 +1,38 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+func TestDuePolicy(t *testing.T) {
+	loc := time.FixedZone("test", -7*3600)
+	now := time.Date(2024, 3, 5, 8, 15, 0, 0, loc)
+	policy := DuePolicy{Priorities: []task.Priority{task.PriorityCritical}, Hour: 9, Minute: 30}
+
+	tests := []struct {
+		name   string
+		line   string
+		policy DuePolicy
+		want   time.Time
+	}{
+		{"Priority due today", "- [ ] !! A1 urgent", policy, time.Date(2024, 3, 5, 9, 30, 0, 0, loc)},
+		{"Priority without due date", "- [ ] !! B1 soon", policy, time.Time{}},
+		{"Whole-day task date", "- [ ] !! C1 renew <2024-03-08 Fri>", policy, time.Date(2024, 3, 8, 9, 30, 0, 0, time.Local)},
+		{"Task date with time", "- [ ] !! D1 call <2024-03-08 Fri 14:00>", policy, time.Date(2024, 3, 8, 14, 0, 0, 0, time.Local)},
+		{"Task date beats priority", "- [ ] !! A1 plan <2024-03-09 Sat>", policy, time.Date(2024, 3, 9, 9, 30, 0, 0, time.Local)},
+		{"Ignored task date", "- [ ] !! C1 renew <2024-03-08 Fri>", DuePolicy{IgnoreTaskDates: true, Hour: 9}, time.Time{}},
+		{"Default policy", "- [ ] !! B2 review", DefaultDuePolicy(), time.Date(2024, 3, 5, 16, 0, 0, 0, loc)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.Due(task.Parse([]string{tt.line})[0], now)
+			if !got.Equal(tt.want) {
+				t.Errorf("Due(%q) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package reminder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+// ListRule sends the tasks of a markdown section, or the tasks with a tag,
+// to a reminder list. Exactly one of Section and Tag is set.
+type ListRule struct {
+	Section string `json:"section,omitempty"` // header text, with or without the leading #s
+	Tag     string `json:"tag,omitempty"`     // tag, with or without the leading #
+	List    string `json:"list"`
+}
+
+// Validate checks that the rule names a list and one section or tag
+func (r ListRule) Validate() error {
+	if strings.TrimSpace(r.List) == "" {
+		return fmt.Errorf("list rule for section '%s' / tag '%s' has no list name", r.Section, r.Tag)
+	}
+	if (normalizeSection(r.Section) == "") == (normalizeTag(r.Tag) == "") {
+		return fmt.Errorf("list rule for list '%s' must set exactly one of section or tag", r.List)
+	}
+	return nil
+}
+
+// Matches reports whether the rule applies to a task
+func (r ListRule) Matches(t task.Task) bool {
+	if section := normalizeSection(r.Section); section != "" {
+		return strings.EqualFold(section, t.Section)
+	}
+	tag := normalizeTag(r.Tag)
+	for _, taskTag := range t.Tags {
+		if strings.EqualFold(tag, taskTag) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListRules are checked in order; the first matching rule picks the list
+type ListRules []ListRule
+
+// ListFor returns the list for a task, or "" for the backend's default list,
+// which is named defaultList. A rule naming the default list returns "", the
+// list backends report for reminders in it.
+func (rules ListRules) ListFor(t task.Task, defaultList string) string {
+	for _, rule := range rules {
+		if rule.Matches(t) {
+			if rule.List == defaultList {
+				return ""
+			}
+			return rule.List
+		}
+	}
+	return ""
+}
+
+// Lists returns the distinct list names used by the rules, in rule order
+func (rules ListRules) Lists() []string {
+	var lists []string
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if !seen[rule.List] {
+			seen[rule.List] = true
+			lists = append(lists, rule.List)
+		}
+	}
+	return lists
+}
+
+// normalizeSection strips markdown header markers from a section name
+func normalizeSection(section string) string {
+	return strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(section), "#"))
+}
+
+// normalizeTag strips the leading # from a tag
+func normalizeTag(tag string) string {
+	return strings.TrimPrefix(strings.TrimSpace(tag), "#")
+}
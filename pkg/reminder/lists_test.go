@@ -0,0 +1,82 @@
+package reminder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+func TestListRules(t *testing.T) {
+	rules := ListRules{
+		{Tag: "#ferris", List: "Homelab"},
+		{Section: "## Work", List: "Work"},
+		{Section: "Home", List: "Homelab"},
+		{Tag: "#inbox", List: "Todo"},
+	}
+	tasks := task.Parse([]string{
+		"## Work",
+		"- [ ] !! A1 quarterly plan",
+		"- [ ] !! B2 #ferris patch the nas",
+		"# Home",
+		"- [ ] !! fix the fence",
+		"# Errands",
+		"- [ ] !! buy milk",
+		"- [ ] !! #inbox sort mail",
+	})
+
+	// "Todo" is the default list
+	want := []string{"Work", "Homelab", "Homelab", "", ""}
+	for i, tt := range tasks {
+		if got := rules.ListFor(tt, "Todo"); got != want[i] {
+			t.Errorf("ListFor(%q) = %q, want %q", tt.Line, got, want[i])
+		}
+	}
+	if got := strings.Join(rules.Lists(), ","); got != "Homelab,Work,Todo" {
+		t.Errorf("Lists() = %q, want Homelab,Work,Todo", got)
+	}
+}
+
+func TestListRuleValidate(t *testing.T) {
+	tests := []struct {
+		rule    ListRule
+		wantErr bool
+	}{
+		{ListRule{Section: "## Work", List: "Work"}, false},
+		{ListRule{Tag: "ferris", List: "Homelab"}, false},
+		{ListRule{Section: "Work"}, true},
+		{ListRule{List: "Nothing to match"}, true},
+		{ListRule{Section: "Work", Tag: "work", List: "Both"}, true},
+		{ListRule{Section: "##", List: "Only markers"}, true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.rule, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSyncMovesBetweenLists(t *testing.T) {
+	backend := NewMemoryBackend()
+	id, _ := backend.Add(Reminder{Key: "k1", Title: "Task", Notes: "kept"})
+
+	result, err := Sync(backend, []Reminder{
+		{Key: "k1", Title: "Task", List: "Work"},
+		{Key: "k2", Title: "Other", List: "Homelab"},
+	})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if want := (SyncResult{Added: 1, Updated: 1}); *result != want {
+		t.Errorf("Sync() = %+v, want %+v", *result, want)
+	}
+
+	reminders, _ := backend.List()
+	if reminders[0].ID != id || reminders[0].List != "Work" || reminders[0].Notes != "kept" {
+		t.Errorf("moved reminder = %+v, want it moved to Work with its notes", reminders[0])
+	}
+	if reminders[1].List != "Homelab" {
+		t.Errorf("added reminder = %+v, want it in Homelab", reminders[1])
+	}
+}
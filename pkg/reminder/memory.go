@@ -0,0 +1,78 @@
+package reminder
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// MemoryBackend keeps reminders in memory.
+// It is useful for tests and dry runs.
+type MemoryBackend struct {
+	Reminders []Reminder
+	nextID    int
+}
+
+// NewMemoryBackend creates an empty in-memory backend
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{}
+}
+
+// List returns a copy of the stored reminders
+func (m *MemoryBackend) List() ([]Reminder, error) {
+	return append([]Reminder{}, m.Reminders...), nil
+}
+
+// Add stores a reminder and assigns it an ID
+func (m *MemoryBackend) Add(r Reminder) (string, error) {
+	m.nextID++
+	r.ID = strconv.Itoa(m.nextID)
+	m.Reminders = append(m.Reminders, r)
+	return r.ID, nil
+}
+
+// Update replaces the title, notes, notes hash, due date, priority, flag, key and list of a stored reminder
+func (m *MemoryBackend) Update(r Reminder) error {
+	index, err := m.find(r.ID)
+	if err != nil {
+		return err
+	}
+	m.Reminders[index].Title = r.Title
+	m.Reminders[index].Notes = r.Notes
+	m.Reminders[index].NotesHash = r.NotesHash
+	m.Reminders[index].Due = r.Due
+	m.Reminders[index].Priority = r.Priority
+	m.Reminders[index].Flagged = r.Flagged
+	m.Reminders[index].Key = r.Key
+	m.Reminders[index].List = r.List
+	return nil
+}
+
+// Complete marks a stored reminder as completed
+func (m *MemoryBackend) Complete(id string) error {
+	index, err := m.find(id)
+	if err != nil {
+		return err
+	}
+	m.Reminders[index].Completed = true
+	return nil
+}
+
+// Delete removes a stored reminder
+func (m *MemoryBackend) Delete(id string) error {
+	index, err := m.find(id)
+	if err != nil {
+		return err
+	}
+	m.Reminders = append(m.Reminders[:index], m.Reminders[index+1:]...)
+	return nil
+}
+
+// find returns the index of the reminder with id
+func (m *MemoryBackend) find(id string) (int, error) {
+	for i, r := range m.Reminders {
+		if r.ID == id {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("reminder '%s' not found", id)
+}
@@ -0,0 +1,83 @@
+package reminder
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+// sourcePrefix starts the file:line backlink that ends the notes written by a sync
+const sourcePrefix = "Source: "
+
+// Precompiled regex patterns for better performance
+var (
+	sourceLineRegex  = regexp.MustCompile(`^` + sourcePrefix + `.+:\d+$`)
+	legacyNotesRegex = regexp.MustCompile(`^Priority: \w+(, Effort: \d+)?$`)
+)
+
+// TaskNotes returns the notes for a task's reminder: its priority and effort,
+// its detail lines and a file:line backlink to the task in filePath
+func TaskNotes(t task.Task, filePath string) string {
+	var b strings.Builder
+	b.WriteString("Priority: " + t.Priority.String())
+	if t.Effort > 0 {
+		fmt.Fprintf(&b, ", Effort: %d", t.Effort)
+	}
+	b.WriteString("\n")
+	for _, detail := range t.Details {
+		b.WriteString("- " + detail + "\n")
+	}
+	fmt.Fprintf(&b, "\n%s%s:%d", sourcePrefix, filePath, t.LineNumber)
+	return b.String()
+}
+
+// notesHashLength is the number of hex digits kept of a notes hash
+const notesHashLength = 12
+
+// notesHash returns the hash of notes stored with a reminder as
+// Reminder.NotesHash. Line endings and surrounding whitespace, which reminder
+// apps may change, do not count.
+func notesHash(notes string) string {
+	notes = strings.ReplaceAll(notes, "\r\n", "\n")
+	notes = strings.TrimSpace(strings.ReplaceAll(notes, "\r", "\n"))
+	sum := sha1.Sum([]byte(notes))
+	return hex.EncodeToString(sum[:])[:notesHashLength]
+}
+
+// refreshNotes reports whether a sync should replace the notes of the current
+// reminder with the wanted ones, whose NotesHash is set. Notes are regenerated
+// while they still match the hash of the notes the last sync wrote, so that
+// changes to the task's priority, effort, details or line show up; notes
+// edited in the reminder app are kept. Reminders synced before hashes were
+// stored are refreshed when they hold the bare priority line of earlier
+// versions or differ only in the line of the backlink. Equal notes are written
+// again only to store a missing or stale hash.
+func refreshNotes(current, want Reminder) bool {
+	switch {
+	case current.Notes == want.Notes:
+		return current.NotesHash != want.NotesHash
+	case current.NotesHash != "":
+		return current.NotesHash == notesHash(current.Notes)
+	case legacyNotesRegex.MatchString(strings.TrimSpace(current.Notes)):
+		return true
+	default:
+		return stripSource(current.Notes) != "" && stripSource(current.Notes) == stripSource(want.Notes)
+	}
+}
+
+// stripSource returns notes without the backlink written by TaskNotes, or ""
+// when the notes do not end with one
+func stripSource(notes string) string {
+	notes = strings.TrimRight(notes, "\n")
+	index := strings.LastIndex(notes, "\n")
+	if !sourceLineRegex.MatchString(notes[index+1:]) {
+		return ""
+	}
+	path := strings.TrimPrefix(notes[index+1:], sourcePrefix)
+	path = path[:strings.LastIndex(path, ":")]
+	return notes[:index+1] + sourcePrefix + path
+}
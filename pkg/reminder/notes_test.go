@@ -0,0 +1,74 @@
+package reminder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+func TestTaskNotes(t *testing.T) {
+	tasks := task.Parse([]string{
+		"- [.] !! A2 Ship release",
+		"  check changelog",
+		"  tag build",
+		"- [ ] Plain task",
+	})
+
+	tests := []struct {
+		name string
+		task task.Task
+		want string
+	}{
+		{
+			name: "priority effort and details",
+			task: tasks[0],
+			want: "Priority: Critical, Effort: 2\n- check changelog\n- tag build\n\nSource: todo.md:1",
+		},
+		{
+			name: "no effort or details",
+			task: tasks[1],
+			want: "Priority: None\n\nSource: todo.md:4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TaskNotes(tt.task, "todo.md"); got != tt.want {
+				t.Errorf("TaskNotes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefreshNotes(t *testing.T) {
+	want := Reminder{Notes: "Priority: High\n- detail\n\nSource: /notes/todo.md:7"}
+	want.NotesHash = notesHash(want.Notes)
+	generated := "Priority: Medium\n\nSource: /notes/todo.md:3"
+
+	tests := []struct {
+		name    string
+		current Reminder
+		want    bool
+	}{
+		{"same notes", want, false},
+		{"same notes without hash", Reminder{Notes: want.Notes}, true},
+		{"generated notes of an earlier sync", Reminder{Notes: generated, NotesHash: notesHash(generated)}, true},
+		{"generated notes with other line endings", Reminder{Notes: strings.ReplaceAll(generated, "\n", "\r\n") + "\n", NotesHash: notesHash(generated)}, true},
+		{"edited in app", Reminder{Notes: generated + "\ncall Bob first", NotesHash: notesHash(generated)}, false},
+		{"cleared in app", Reminder{NotesHash: notesHash(generated)}, false},
+		{"legacy priority line", Reminder{Notes: "Priority: High, Effort: 3"}, true},
+		{"task moved before hashes", Reminder{Notes: "Priority: High\n- detail\n\nSource: /notes/todo.md:3"}, true},
+		{"edited before hashes", Reminder{Notes: "Priority: High\n- detail\ncall Bob first\n\nSource: /notes/todo.md:3"}, false},
+		{"backlink removed in app", Reminder{Notes: "my own notes"}, false},
+		{"empty notes", Reminder{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := refreshNotes(tt.current, want); got != tt.want {
+				t.Errorf("refreshNotes(%+v) = %v, want %v", tt.current, got, tt.want)
+			}
+		})
+	}
+}
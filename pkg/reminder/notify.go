@@ -0,0 +1,175 @@
+package reminder
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+// Notification methods
+const (
+	NotifySend = "notify-send" // the libnotify command line tool
+	NotifyDBus = "dbus"        // org.freedesktop.Notifications called with gdbus
+)
+
+// DefaultAlertLimit is the number of alerts sent one by one before the rest
+// are summarized in a single notification
+const DefaultAlertLimit = 5
+
+// Alert is a desktop notification about a task
+type Alert struct {
+	Title  string
+	Body   string
+	Urgent bool
+}
+
+// Alerts returns an alert for every open task that is active (!!) or overdue.
+// A task is overdue when its own date, at the policy's time of day for
+// whole-day dates, is before now. The policy's priority rule only sets the
+// due time shown for active tasks; it never makes an idle task overdue.
+func Alerts(tasks []task.Task, policy DuePolicy, now time.Time) []Alert {
+	var alerts []Alert
+	for _, t := range tasks {
+		if task.IsCompleted(strings.TrimLeft(t.Line, " \t")) {
+			continue
+		}
+		active := t.Active && !t.Subtask
+		due := policy.Due(t, now)
+		overdue := t.Date != nil && !policy.IgnoreTaskDates && due.Before(now)
+		if !active && !overdue {
+			continue
+		}
+
+		alert := Alert{Title: "Active: " + t.Title, Urgent: t.Priority == task.PriorityCritical}
+		if overdue {
+			alert.Title = "Overdue: " + t.Title
+			alert.Urgent = true
+		}
+		var body []string
+		if t.Priority != task.PriorityNone {
+			body = append(body, "Priority: "+t.Priority.String())
+		}
+		if !due.IsZero() {
+			body = append(body, "Due: "+due.Format("Mon Jan 2 15:04"))
+		}
+		if t.Section != "" {
+			body = append(body, "Section: "+t.Section)
+		}
+		alert.Body = strings.Join(body, "\n")
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+// Notifier sends desktop notifications on Linux through notify-send or the
+// freedesktop notifications D-Bus interface
+type Notifier struct {
+	Method  string // NotifySend or NotifyDBus
+	AppName string
+	Timeout time.Duration // how long a notification is shown; zero for the server default
+}
+
+// NewNotifier creates a notifier using method; an empty method selects notify-send
+func NewNotifier(method string) (*Notifier, error) {
+	switch method {
+	case "", NotifySend:
+		method = NotifySend
+	case NotifyDBus:
+	default:
+		return nil, fmt.Errorf("unknown notification method '%s' (use %s or %s)", method, NotifySend, NotifyDBus)
+	}
+	return &Notifier{Method: method, AppName: "taskmasterra"}, nil
+}
+
+// Notify sends a single alert
+func (n *Notifier) Notify(alert Alert) error {
+	var cmd []string
+	if n.Method == NotifyDBus {
+		cmd = n.dbusCommand(alert)
+	} else {
+		cmd = n.notifySendCommand(alert)
+	}
+
+	c := ExecCommand(cmd[0], cmd[1:]...)
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("failed to send notification '%s' via %s: %w (stderr: %s)", alert.Title, cmd[0], err, stderr.String())
+	}
+	return nil
+}
+
+// NotifyAll sends up to limit alerts one by one and summarizes the rest in a
+// final notification; a limit of zero sends every alert. It returns the
+// number of notifications sent.
+func (n *Notifier) NotifyAll(alerts []Alert, limit int) (int, error) {
+	batch := alerts
+	if limit > 0 && len(alerts) > limit {
+		batch = append(alerts[:limit:limit], summarize(alerts[limit:]))
+	}
+	for i, alert := range batch {
+		if err := n.Notify(alert); err != nil {
+			return i, err
+		}
+	}
+	return len(batch), nil
+}
+
+// summarize combines alerts into a single alert listing their titles
+func summarize(alerts []Alert) Alert {
+	summary := Alert{Title: fmt.Sprintf("%d more tasks need attention", len(alerts))}
+	titles := make([]string, len(alerts))
+	for i, alert := range alerts {
+		titles[i] = alert.Title
+		summary.Urgent = summary.Urgent || alert.Urgent
+	}
+	summary.Body = strings.Join(titles, "\n")
+	return summary
+}
+
+// notifySendCommand returns the notify-send command line for an alert
+func (n *Notifier) notifySendCommand(alert Alert) []string {
+	urgency := "normal"
+	if alert.Urgent {
+		urgency = "critical"
+	}
+	cmd := []string{NotifySend, "--app-name=" + n.AppName, "--urgency=" + urgency}
+	if n.Timeout > 0 {
+		cmd = append(cmd, "--expire-time="+strconv.FormatInt(n.Timeout.Milliseconds(), 10))
+	}
+	// "--" keeps titles starting with a dash from being read as options
+	return append(cmd, "--", alert.Title, alert.Body)
+}
+
+// dbusCommand returns the gdbus command line that calls
+// org.freedesktop.Notifications.Notify for an alert
+func (n *Notifier) dbusCommand(alert Alert) []string {
+	urgency := 1
+	if alert.Urgent {
+		urgency = 2
+	}
+	timeout := int64(-1)
+	if n.Timeout > 0 {
+		timeout = n.Timeout.Milliseconds()
+	}
+	return []string{
+		"gdbus", "call", "--session",
+		"--dest", "org.freedesktop.Notifications",
+		"--object-path", "/org/freedesktop/Notifications",
+		"--method", "org.freedesktop.Notifications.Notify",
+		gvariantString(n.AppName), "0", `""`, gvariantString(alert.Title), gvariantString(alert.Body),
+		"[]", fmt.Sprintf("{'urgency': <byte %d>}", urgency), strconv.FormatInt(timeout, 10),
+	}
+}
+
+// gvariantString quotes s as a GVariant text-format string
+func gvariantString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return "'" + s + "'"
+}
@@ -0,0 +1,101 @@
+package reminder
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+func TestAlerts(t *testing.T) {
+	lines := []string{
+		"# Work",
+		"- [ ] !! A1 deploy",
+		"- [ ] B2 renew certificate <2024-03-04 Mon>",
+		"- [ ] C1 plan offsite <2024-03-20 Wed>",
+		"- [x] !! done already",
+		"- [ ] someday",
+	}
+	now := time.Date(2024, 3, 5, 10, 0, 0, 0, time.Local)
+	policy := DuePolicy{Hour: 9}
+
+	alerts := Alerts(task.Parse(lines), policy, now)
+	if len(alerts) != 2 {
+		t.Fatalf("Alerts() = %+v, want 2 alerts", alerts)
+	}
+	if alerts[0].Title != "Active: deploy" || !alerts[0].Urgent || !strings.Contains(alerts[0].Body, "Section: Work") {
+		t.Errorf("alerts[0] = %+v, want urgent active alert", alerts[0])
+	}
+	if alerts[1].Title != "Overdue: renew certificate" || !alerts[1].Urgent || !strings.Contains(alerts[1].Body, "Due: Mon Mar 4 09:00") {
+		t.Errorf("alerts[1] = %+v, want overdue alert", alerts[1])
+	}
+}
+
+func TestAlerts_PriorityDueOnlyForActive(t *testing.T) {
+	lines := []string{
+		"- [ ] A1 some not active task",
+		"- [ ] B2 another idle task",
+		"- [ ] !! B1 active task",
+	}
+	now := time.Date(2024, 3, 5, 17, 0, 0, 0, time.Local) // after the default 16:00
+
+	alerts := Alerts(task.Parse(lines), DefaultDuePolicy(), now)
+	if len(alerts) != 1 {
+		t.Fatalf("Alerts() = %+v, want only the active task", alerts)
+	}
+	if alerts[0].Title != "Active: active task" || !strings.Contains(alerts[0].Body, "Due: Tue Mar 5 16:00") {
+		t.Errorf("alerts[0] = %+v, want active alert with its due time", alerts[0])
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	originalExecCommand := ExecCommand
+	defer func() { ExecCommand = originalExecCommand }()
+
+	var commands [][]string
+	ExecCommand = func(command string, args ...string) *exec.Cmd {
+		commands = append(commands, append([]string{command}, args...))
+		return helperCommand(command, args...)
+	}
+
+	alerts := []Alert{
+		{Title: "-starts with a dash", Body: "line one\nline two", Urgent: true},
+		{Title: "Second"},
+		{Title: "Third", Urgent: true},
+	}
+
+	notifier, err := NewNotifier("")
+	if err != nil {
+		t.Fatalf("NewNotifier() error = %v", err)
+	}
+	sent, err := notifier.NotifyAll(alerts, 1)
+	if err != nil || sent != 2 {
+		t.Fatalf("NotifyAll() = %d, %v, want 2 notifications", sent, err)
+	}
+	want := []string{"notify-send", "--app-name=taskmasterra", "--urgency=critical", "--", "-starts with a dash", "line one\nline two"}
+	if strings.Join(commands[0], "|") != strings.Join(want, "|") {
+		t.Errorf("first command = %q, want %q", commands[0], want)
+	}
+	if last := commands[1]; last[len(last)-2] != "2 more tasks need attention" || last[len(last)-1] != "Second\nThird" || last[2] != "--urgency=critical" {
+		t.Errorf("summary command = %q", last)
+	}
+
+	commands = nil
+	notifier, _ = NewNotifier(NotifyDBus)
+	notifier.Timeout = 5 * time.Second
+	if err := notifier.Notify(Alert{Title: "It's due", Body: "now"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	got := strings.Join(commands[0], " ")
+	for _, fragment := range []string{"gdbus call --session", "--method org.freedesktop.Notifications.Notify", `'taskmasterra' 0 "" 'It\'s due' 'now'`, "{'urgency': <byte 1>} 5000"} {
+		if !strings.Contains(got, fragment) {
+			t.Errorf("D-Bus command %q missing %q", got, fragment)
+		}
+	}
+
+	if _, err := NewNotifier("carrier-pigeon"); err == nil {
+		t.Error("NewNotifier() with an unknown method should fail")
+	}
+}
@@ -4,22 +4,67 @@ import (
 	"bytes"
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // ExecCommand is a variable that holds the exec.Command function.
 // This allows us to replace it with a mock during testing.
 var ExecCommand = exec.Command
 
-// Service handles interactions with macOS Reminders
+// Field and record separators used to read reminders back from AppleScript
+const (
+	fieldSeparator  = "\x1f"
+	recordSeparator = "\x1e"
+)
+
+// keyPrefix starts the last line of a reminder's notes that holds its sync key
+const keyPrefix = "taskmasterra-key: "
+
+// encodeNotes appends the sync key and notes hash to the notes stored in Reminders.app
+func encodeNotes(notes, key, hash string) string {
+	if key == "" {
+		return notes
+	}
+	line := keyPrefix + key
+	if hash != "" {
+		line += " " + hash
+	}
+	if notes == "" {
+		return line
+	}
+	return notes + "\n\n" + line
+}
+
+// decodeNotes separates the sync key and notes hash from notes read from Reminders.app
+func decodeNotes(body string) (string, string, string) {
+	trimmed := strings.TrimRight(body, "\n")
+	index := strings.LastIndex(trimmed, "\n")
+	last := trimmed[index+1:]
+	if !strings.HasPrefix(last, keyPrefix) {
+		return body, "", ""
+	}
+	key, hash, _ := strings.Cut(strings.TrimSpace(strings.TrimPrefix(last, keyPrefix)), " ")
+	if index < 0 {
+		return "", key, hash
+	}
+	return strings.TrimRight(trimmed[:index], "\n"), key, hash
+}
+
+// Service handles interactions with macOS Reminders.
+// It implements Backend.
 type Service struct {
-	ListName string
+	ListName  string
+	Lists     []string  // other lists read by List and written by ApplyBatch
+	DuePolicy DuePolicy // time of day used by AddReminder for due dates
 }
 
 // NewService creates a new reminder service
 func NewService(listName string) *Service {
 	return &Service{
-		ListName: listName,
+		ListName:  listName,
+		DuePolicy: DefaultDuePolicy(),
 	}
 }
 
@@ -63,16 +108,17 @@ func (s *Service) AddReminder(task string, withDueDate bool, note string) error
 	var script string
 	if withDueDate {
 		script = fmt.Sprintf(`
+			%s
 			tell application "Reminders"
 				if exists list "%s" then
 					tell list "%s"
-						make new reminder with properties {name:"%s", body:"%s", due date:(current date)}
+						make new reminder with properties {name:"%s", body:"%s", due date:dueDate}
 					end tell
 				else
 					error "List '%s' does not exist"
 				end if
 			end tell
-		`, escapeAppleScriptString(s.ListName), escapeAppleScriptString(s.ListName), escapedTask, escapedNote, s.ListName)
+		`, appleScriptDate("dueDate", s.DuePolicy.At(time.Now())), escapeAppleScriptString(s.ListName), escapeAppleScriptString(s.ListName), escapedTask, escapedNote, s.ListName)
 	} else {
 		script = fmt.Sprintf(`
 			tell application "Reminders"
@@ -96,4 +142,376 @@ func (s *Service) AddReminder(task string, withDueDate bool, note string) error
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
+
+// runScript runs an AppleScript and returns its trimmed output
+func (s *Service) runScript(script string) (string, error) {
+	cmd := ExecCommand("osascript", "-e", script)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w (stderr: %s)", err, stderr.String())
+	}
+
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// appleScriptDate returns statements that set the AppleScript variable name to t
+func appleScriptDate(name string, t time.Time) string {
+	t = t.Local()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	// The day is reset first so that changing the month cannot overflow
+	return fmt.Sprintf(`
+		set %[1]s to current date
+		set day of %[1]s to 1
+		set year of %[1]s to %[2]d
+		set month of %[1]s to %[3]d
+		set day of %[1]s to %[4]d
+		set time of %[1]s to %[5]d`,
+		name, t.Year(), int(t.Month()), t.Day(), int(t.Sub(midnight).Seconds()))
+}
+
+// List returns all reminders in the default list and the other lists.
+// Reminders in the default list have an empty List.
+func (s *Service) List() ([]Reminder, error) {
+	script := fmt.Sprintf(`
+		set fieldSep to character id 31
+		set recordSep to character id 30
+		set output to ""
+		tell application "Reminders"
+			repeat with listName in {%s}
+				set listName to listName as string
+				if exists list listName then
+					repeat with r in reminders of list listName
+						set dueText to ""
+						set d to due date of r
+						if d is not missing value then
+							set dueText to ((year of d) as string) & "-" & ((month of d as integer) as string) & "-" & ((day of d) as string) & "-" & ((time of d) as string)
+						end if
+						set noteText to body of r
+						if noteText is missing value then set noteText to ""
+						set output to output & listName & fieldSep & (id of r) & fieldSep & (name of r) & fieldSep & ((completed of r) as string) & fieldSep & dueText & fieldSep & ((priority of r) as string) & fieldSep & ((flagged of r) as string) & fieldSep & noteText & recordSep
+					end repeat
+				end if
+			end repeat
+		end tell
+		return output
+	`, appleScriptList(s.allLists()))
+
+	output, err := s.runScript(script)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reminders in '%s' via AppleScript: %w", strings.Join(s.allLists(), "', '"), err)
+	}
+
+	reminders, err := parseReminderList(output)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reminders {
+		if reminders[i].List == s.ListName {
+			reminders[i].List = ""
+		}
+	}
+	return reminders, nil
+}
+
+// allLists returns the default list followed by the other lists, without duplicates
+func (s *Service) allLists() []string {
+	lists := []string{s.ListName}
+	for _, list := range s.Lists {
+		if list != "" && !containsList(lists, list) {
+			lists = append(lists, list)
+		}
+	}
+	return lists
+}
+
+// listFor returns the list a reminder belongs in
+func (s *Service) listFor(r Reminder) string {
+	if r.List == "" {
+		return s.ListName
+	}
+	return r.List
+}
+
+// containsList reports whether lists contains list
+func containsList(lists []string, list string) bool {
+	for _, l := range lists {
+		if l == list {
+			return true
+		}
+	}
+	return false
+}
+
+// appleScriptList returns an AppleScript list of strings
+func appleScriptList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = `"` + escapeAppleScriptString(value) + `"`
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// parseReminderList parses the output of the List script
+func parseReminderList(output string) ([]Reminder, error) {
+	var reminders []Reminder
+	for _, record := range strings.Split(output, recordSeparator) {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), fieldSeparator, 8)
+		if len(fields) != 8 {
+			return nil, fmt.Errorf("unexpected reminder record %q", record)
+		}
+		list := fields[0]
+		fields = fields[1:]
+		priority, err := strconv.Atoi(strings.TrimSpace(fields[4]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid priority %q for reminder '%s'", fields[4], fields[1])
+		}
+		notes, key, hash := decodeNotes(fields[6])
+		r := Reminder{
+			ID:        strings.TrimSpace(fields[0]),
+			Key:       key,
+			NotesHash: hash,
+			Title:     fields[1],
+			Completed: fields[2] == "true",
+			Priority:  priority,
+			Flagged:   fields[5] == "true",
+			Notes:     notes,
+			List:      list,
+		}
+		if fields[3] != "" {
+			due, err := parseAppleScriptDate(fields[3])
+			if err != nil {
+				return nil, fmt.Errorf("invalid due date for reminder '%s': %w", r.Title, err)
+			}
+			r.Due = due
+		}
+		reminders = append(reminders, r)
+	}
+	return reminders, nil
+}
+
+// parseAppleScriptDate parses "year-month-day-seconds" as written by the List script
+func parseAppleScriptDate(value string) (time.Time, error) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 4 {
+		return time.Time{}, fmt.Errorf("unexpected date %q", value)
+	}
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unexpected date %q", value)
+		}
+		numbers[i] = n
+	}
+	return time.Date(numbers[0], time.Month(numbers[1]), numbers[2], 0, 0, numbers[3], 0, time.Local), nil
+}
+
+// Add creates a reminder in its list and returns its ID
+func (s *Service) Add(r Reminder) (string, error) {
+	listName := escapeAppleScriptString(s.listFor(r))
+	dueStatements, setDue := "", ""
+	if !r.Due.IsZero() {
+		dueStatements = appleScriptDate("dueDate", r.Due)
+		setDue = "set due date of newReminder to dueDate"
+	}
+
+	script := fmt.Sprintf(`%[1]s
+		tell application "Reminders"
+			if not (exists list "%[2]s") then error "List '%[2]s' does not exist"
+			tell list "%[2]s"
+				set newReminder to make new reminder with properties {name:"%[3]s", body:"%[4]s", priority:%[6]d, flagged:%[7]t}
+				%[5]s
+				return id of newReminder
+			end tell
+		end tell
+	`, dueStatements, listName, escapeAppleScriptString(r.Title), escapeAppleScriptString(encodeNotes(r.Notes, r.Key, r.NotesHash)), setDue, r.Priority, r.Flagged)
+
+	id, err := s.runScript(script)
+	if err != nil {
+		return "", fmt.Errorf("failed to add reminder '%s' to list '%s' via AppleScript: %w", r.Title, s.listFor(r), err)
+	}
+
+	return strings.TrimSpace(id), nil
+}
+
+// Update replaces the title, notes, due date, priority, flag and key of a
+// reminder and moves it to its list, creating the list when missing
+func (s *Service) Update(r Reminder) error {
+	listName := escapeAppleScriptString(s.listFor(r))
+	dueStatements, setDue := "", "set due date of r to missing value"
+	if !r.Due.IsZero() {
+		dueStatements = appleScriptDate("dueDate", r.Due)
+		setDue = "set due date of r to dueDate"
+	}
+
+	script := fmt.Sprintf(`%[1]s
+		tell application "Reminders"
+			if not (exists list "%[2]s") then make new list with properties {name:"%[2]s"}
+			set r to reminder id "%[3]s"
+			set name of r to "%[4]s"
+			set body of r to "%[5]s"
+			set priority of r to %[7]d
+			set flagged of r to %[8]t
+			%[6]s
+			if name of container of r is not "%[2]s" then move r to list "%[2]s"
+		end tell
+	`, dueStatements, listName, escapeAppleScriptString(r.ID),
+		escapeAppleScriptString(r.Title), escapeAppleScriptString(encodeNotes(r.Notes, r.Key, r.NotesHash)), setDue, r.Priority, r.Flagged)
+
+	if _, err := s.runScript(script); err != nil {
+		return fmt.Errorf("failed to update reminder '%s' in list '%s' via AppleScript: %w", r.Title, s.listFor(r), err)
+	}
+
+	return nil
+}
+
+// Complete marks a reminder as completed
+func (s *Service) Complete(id string) error {
+	script := fmt.Sprintf(`
+		tell application "Reminders"
+			set completed of (reminder id "%s") to true
+		end tell
+	`, escapeAppleScriptString(id))
+
+	if _, err := s.runScript(script); err != nil {
+		return fmt.Errorf("failed to complete reminder '%s' via AppleScript: %w", id, err)
+	}
+
+	return nil
+}
+
+// Delete removes a reminder
+func (s *Service) Delete(id string) error {
+	script := fmt.Sprintf(`
+		tell application "Reminders"
+			delete (reminder id "%s")
+		end tell
+	`, escapeAppleScriptString(id))
+
+	if _, err := s.runScript(script); err != nil {
+		return fmt.Errorf("failed to delete reminder '%s' via AppleScript: %w", id, err)
+	}
+
+	return nil
+}
+
+// Result markers written by the ApplyBatch script for each change
+const (
+	batchOK    = "ok"
+	batchError = "error"
+)
+
+// ApplyBatch applies changes with a single AppleScript program, so that a sync
+// starts osascript once. Lists that reminders are added to or moved to are
+// created when missing. Each change runs in its own try block and reports
+// its result, so one failing change does not stop the others.
+func (s *Service) ApplyBatch(changes []Change) ([]error, error) {
+	// Dates are built before talking to Reminders.app
+	var dates, body strings.Builder
+	var lists []string
+	for i, change := range changes {
+		r := change.Reminder
+		dueVar := fmt.Sprintf("dueDate%d", i)
+		if !r.Due.IsZero() && (change.Op == OpAdd || change.Op == OpUpdate) {
+			dates.WriteString(appleScriptDate(dueVar, r.Due))
+		} else {
+			dueVar = "missing value"
+		}
+		listName := escapeAppleScriptString(s.listFor(r))
+
+		var statements string
+		switch change.Op {
+		case OpAdd:
+			statements = fmt.Sprintf(`
+				tell list "%s"
+					set r to make new reminder with properties {name:"%s", body:"%s", priority:%d, flagged:%t}
+				end tell
+				set changeResult to id of r`,
+				listName, escapeAppleScriptString(r.Title), escapeAppleScriptString(encodeNotes(r.Notes, r.Key, r.NotesHash)), r.Priority, r.Flagged)
+			if dueVar != "missing value" {
+				statements += `
+				set due date of r to ` + dueVar
+			}
+		case OpUpdate:
+			statements = fmt.Sprintf(`
+				set r to reminder id "%s"
+				set name of r to "%s"
+				set body of r to "%s"
+				set priority of r to %d
+				set flagged of r to %t
+				set due date of r to %s
+				if name of container of r is not "%[7]s" then move r to list "%[7]s"`,
+				escapeAppleScriptString(r.ID), escapeAppleScriptString(r.Title), escapeAppleScriptString(encodeNotes(r.Notes, r.Key, r.NotesHash)), r.Priority, r.Flagged, dueVar, listName)
+		case OpComplete:
+			statements = fmt.Sprintf(`
+				set completed of (reminder id "%s") to true`, escapeAppleScriptString(r.ID))
+		case OpDelete:
+			statements = fmt.Sprintf(`
+				delete (reminder id "%s")`, escapeAppleScriptString(r.ID))
+		default:
+			return nil, fmt.Errorf("unknown operation %d for reminder '%s'", change.Op, r.Title)
+		}
+		if (change.Op == OpAdd || change.Op == OpUpdate) && !containsList(lists, s.listFor(r)) {
+			lists = append(lists, s.listFor(r))
+		}
+
+		fmt.Fprintf(&body, `
+			try
+				set changeResult to ""%s
+				set output to output & "%s" & fieldSep & changeResult & recordSep
+			on error errMsg
+				set output to output & "%s" & fieldSep & errMsg & recordSep
+			end try`, statements, batchOK, batchError)
+	}
+
+	script := fmt.Sprintf(`
+		set fieldSep to character id 31
+		set recordSep to character id 30
+		set output to ""%s
+		tell application "Reminders"
+			repeat with listName in {%s}
+				set listName to listName as string
+				if not (exists list listName) then make new list with properties {name:listName}
+			end repeat%s
+		end tell
+		return output
+	`, dates.String(), appleScriptList(lists), body.String())
+
+	output, err := s.runScript(script)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply %d reminder changes to '%s' via AppleScript: %w", len(changes), strings.Join(s.allLists(), "', '"), err)
+	}
+
+	return parseBatchResults(output, len(changes))
+}
+
+// parseBatchResults parses the output of the ApplyBatch script into the error
+// of each change
+func parseBatchResults(output string, count int) ([]error, error) {
+	var errs []error
+	for _, record := range strings.Split(output, recordSeparator) {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+		status, message, _ := strings.Cut(strings.TrimLeft(record, "\n"), fieldSeparator)
+		switch status {
+		case batchOK:
+			errs = append(errs, nil)
+		case batchError:
+			errs = append(errs, fmt.Errorf("AppleScript error: %s", message))
+		default:
+			return nil, fmt.Errorf("unexpected batch result %q", record)
+		}
+	}
+	if len(errs) != count {
+		return nil, fmt.Errorf("AppleScript returned %d results for %d changes", len(errs), count)
+	}
+	return errs, nil
+}
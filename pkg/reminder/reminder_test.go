@@ -1,10 +1,12 @@
 package reminder
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"strings"
 	"testing"
+	"time"
 )
 
 // helperCommand returns a fake exec.Cmd for testing
@@ -36,6 +38,17 @@ func TestHelperProcess(t *testing.T) {
 
 	// Mock the osascript command
 	if args[0] == "osascript" {
+		if output := os.Getenv("HELPER_OUTPUT"); output != "" {
+			fmt.Print(output)
+			os.Exit(0)
+		}
+		if len(args) > 2 && strings.Contains(args[2], "return id of newReminder") {
+			fmt.Println("x-apple-reminder://NEW-ID")
+		}
+		os.Exit(0)
+	}
+	// Mock the desktop notification commands
+	if args[0] == NotifySend || args[0] == "gdbus" {
 		os.Exit(0)
 	}
 	os.Exit(1)
@@ -219,7 +232,8 @@ func TestAddReminder(t *testing.T) {
 				if tt.note != "" && !strings.Contains(script, escapeAppleScriptString(tt.note)) {
 					t.Errorf("Script doesn't contain escaped note")
 				}
-				if tt.withDueDate && !strings.Contains(script, "due date:(current date)") {
+				// The default due policy sets 16:00, 57600 seconds after midnight
+				if tt.withDueDate && (!strings.Contains(script, "due date:dueDate") || !strings.Contains(script, "set time of dueDate to 57600")) {
 					t.Errorf("Script doesn't contain due date setup")
 				}
 
@@ -237,4 +251,116 @@ func TestAddReminder(t *testing.T) {
 			}
 		})
 	}
-} 
\ No newline at end of file
+}
+
+func TestServiceAdd(t *testing.T) {
+	originalExecCommand := ExecCommand
+	defer func() { ExecCommand = originalExecCommand }()
+
+	due := time.Date(2024, 3, 5, 16, 30, 0, 0, time.Local)
+	ExecCommand = func(command string, args ...string) *exec.Cmd {
+		script := args[1]
+		for _, want := range []string{`name:"Pay \"bills\""`, "set year of dueDate to 2024", "set month of dueDate to 3", "set day of dueDate to 5", "set time of dueDate to 59400", "set due date of newReminder to dueDate"} {
+			if !strings.Contains(script, want) {
+				t.Errorf("Script missing %q:\n%s", want, script)
+			}
+		}
+		return helperCommand(command, args...)
+	}
+
+	id, err := NewService("Todo").Add(Reminder{Title: `Pay "bills"`, Due: due})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if id != "x-apple-reminder://NEW-ID" {
+		t.Errorf("Add() id = %q, want x-apple-reminder://NEW-ID", id)
+	}
+}
+
+func TestServiceCommands(t *testing.T) {
+	originalExecCommand := ExecCommand
+	defer func() { ExecCommand = originalExecCommand }()
+
+	var scripts []string
+	ExecCommand = func(command string, args ...string) *exec.Cmd {
+		scripts = append(scripts, args[1])
+		return helperCommand(command, args...)
+	}
+
+	service := NewService("Todo")
+	if err := service.Update(Reminder{ID: "id-1", Title: "Renamed", Priority: PriorityMedium, List: "Work"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := service.Complete("id-2"); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if err := service.Delete("id-3"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	wants := [][]string{
+		{`set r to reminder id "id-1"`, `set name of r to "Renamed"`, "set priority of r to 5", "set due date of r to missing value",
+			`if not (exists list "Work") then make new list with properties {name:"Work"}`,
+			`if name of container of r is not "Work" then move r to list "Work"`},
+		{`set completed of (reminder id "id-2") to true`},
+		{`delete (reminder id "id-3")`},
+	}
+	for i, want := range wants {
+		for _, fragment := range want {
+			if !strings.Contains(scripts[i], fragment) {
+				t.Errorf("Script %d missing %q:\n%s", i, fragment, scripts[i])
+			}
+		}
+	}
+}
+
+func TestParseReminderList(t *testing.T) {
+	output := "Todo\x1fid-1\x1fFirst\x1ffalse\x1f2024-3-5-57600\x1f1\x1ftrue\x1fline one\nline two\x1e" +
+		"Work\x1fid-2\x1fSecond\x1ftrue\x1f\x1f0\x1ffalse\x1f\x1e"
+
+	reminders, err := parseReminderList(output)
+	if err != nil {
+		t.Fatalf("parseReminderList() error = %v", err)
+	}
+	if len(reminders) != 2 {
+		t.Fatalf("got %d reminders, want 2", len(reminders))
+	}
+
+	want := Reminder{ID: "id-1", Title: "First", Notes: "line one\nline two", Due: time.Date(2024, 3, 5, 16, 0, 0, 0, time.Local), Priority: PriorityHigh, Flagged: true, List: "Todo"}
+	if reminders[0] != want {
+		t.Errorf("reminders[0] = %+v, want %+v", reminders[0], want)
+	}
+	if !reminders[1].Completed || !reminders[1].Due.IsZero() || reminders[1].List != "Work" {
+		t.Errorf("reminders[1] = %+v, want completed without due date in Work", reminders[1])
+	}
+
+	if _, err := parseReminderList("broken"); err == nil {
+		t.Error("Expected error for malformed record")
+	}
+}
+
+func TestEncodeDecodeNotes(t *testing.T) {
+	tests := []struct {
+		notes string
+		key   string
+		hash  string
+		body  string
+	}{
+		{"", "", "", ""},
+		{"Priority: High", "", "", "Priority: High"},
+		{"", "abc12345", "", "taskmasterra-key: abc12345"},
+		{"Priority: High\nuser line", "abc12345", "", "Priority: High\nuser line\n\ntaskmasterra-key: abc12345"},
+		{"Priority: High", "abc12345", "0123456789ab", "Priority: High\n\ntaskmasterra-key: abc12345 0123456789ab"},
+	}
+
+	for _, tt := range tests {
+		body := encodeNotes(tt.notes, tt.key, tt.hash)
+		if body != tt.body {
+			t.Errorf("encodeNotes(%q, %q, %q) = %q, want %q", tt.notes, tt.key, tt.hash, body, tt.body)
+		}
+		notes, key, hash := decodeNotes(body)
+		if notes != tt.notes || key != tt.key || hash != tt.hash {
+			t.Errorf("decodeNotes(%q) = %q, %q, %q, want %q, %q, %q", body, notes, key, hash, tt.notes, tt.key, tt.hash)
+		}
+	}
+}
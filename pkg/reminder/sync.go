@@ -0,0 +1,156 @@
+package reminder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyncResult counts the changes made by Sync
+type SyncResult struct {
+	Added     int
+	Updated   int
+	Removed   int
+	Unchanged int
+}
+
+// Sync makes the backend's keyed reminders match desired, matching them by Key.
+// Missing reminders are added, reminders whose title, due date, priority,
+// flag or list changed are updated and keyed reminders without a desired
+// counterpart are removed.
+// Completion state and notes edited in the reminder app are left as the user
+// set them; notes still as the last sync wrote them follow the task.
+// Reminders without a key were not created by a sync and are never touched,
+// except those written by versions that cleared and refilled the list on
+// every sync (see Reconcile).
+func Sync(backend Backend, desired []Reminder) (*SyncResult, error) {
+	existing, err := backend.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reminders: %w", err)
+	}
+	return Reconcile(backend, existing, desired)
+}
+
+// Reconcile works like Sync with reminders already listed from the backend.
+// All changes are applied in one batch when the backend is a BatchBackend.
+// Changes that fail do not stop the others and are returned as ChangeErrors,
+// with the result counting the changes that succeeded.
+//
+// Versions before keyed reminders cleared the default list and added every
+// active task again on each sync, so those reminders have no key. They are
+// recognized by their "Priority: X[, Effort: N]" notes: the first reminder
+// with a desired reminder's title is adopted (updated with its key) instead
+// of adding a copy, and the rest are removed as the old sync would have done.
+func Reconcile(backend Backend, existing, desired []Reminder) (*SyncResult, error) {
+	result := &SyncResult{}
+	var changes []Change
+	byKey := make(map[string]Reminder)
+	legacy := make(map[string][]Reminder) // unkeyed reminders of earlier versions by title
+	adopted := make(map[string]bool)      // IDs of the legacy reminders given a key
+	for _, r := range existing {
+		if r.Key == "" {
+			if isLegacy(r) {
+				legacy[r.Title] = append(legacy[r.Title], r)
+			}
+			continue
+		}
+		if _, ok := byKey[r.Key]; ok {
+			// Duplicate left over from an interrupted sync
+			changes = append(changes, Change{Op: OpDelete, Reminder: r})
+			continue
+		}
+		byKey[r.Key] = r
+	}
+
+	wanted := make(map[string]bool)
+	for _, want := range desired {
+		if want.Key == "" {
+			return result, fmt.Errorf("reminder '%s' has no key", want.Title)
+		}
+		if wanted[want.Key] {
+			// see Duplicates
+			continue
+		}
+		wanted[want.Key] = true
+		want.NotesHash = notesHash(want.Notes)
+
+		current, ok := byKey[want.Key]
+		if candidates := legacy[want.Title]; !ok && len(candidates) > 0 {
+			current, ok = candidates[0], true
+			legacy[want.Title] = candidates[1:]
+			adopted[current.ID] = true
+		}
+		switch {
+		case !ok:
+			changes = append(changes, Change{Op: OpAdd, Reminder: want})
+		case current.Key == "":
+			// adopt a reminder of an earlier version
+			update := current
+			update.Key = want.Key
+			update.Title = want.Title
+			update.Due = want.Due
+			update.Priority = want.Priority
+			update.Flagged = want.Flagged
+			update.List = want.List
+			update.Notes = want.Notes
+			update.NotesHash = want.NotesHash
+			update.Line = want.Line
+			changes = append(changes, Change{Op: OpUpdate, Reminder: update})
+		case current.Title != want.Title || !current.Due.Equal(want.Due) || current.Priority != want.Priority ||
+			current.Flagged != want.Flagged || current.List != want.List || refreshNotes(current, want):
+			update := current
+			update.Title = want.Title
+			update.Due = want.Due
+			update.Priority = want.Priority
+			update.Flagged = want.Flagged
+			update.List = want.List
+			if refreshNotes(current, want) {
+				update.Notes = want.Notes
+				update.NotesHash = want.NotesHash
+			}
+			update.Line = want.Line
+			changes = append(changes, Change{Op: OpUpdate, Reminder: update})
+		default:
+			result.Unchanged++
+		}
+	}
+
+	for _, r := range existing {
+		if r.Key == "" || wanted[r.Key] || byKey[r.Key].ID != r.ID {
+			continue
+		}
+		changes = append(changes, Change{Op: OpDelete, Reminder: r})
+	}
+	for _, r := range existing {
+		if isLegacy(r) && !adopted[r.ID] {
+			changes = append(changes, Change{Op: OpDelete, Reminder: r})
+		}
+	}
+
+	applied, err := Apply(backend, changes)
+	result.Added = applied[OpAdd]
+	result.Updated = applied[OpUpdate]
+	result.Removed = applied[OpDelete]
+	return result, err
+}
+
+// Duplicates returns the desired reminders that share a key with an earlier
+// one. Reconcile keeps only the first reminder for each key, so these get no
+// reminder of their own.
+func Duplicates(desired []Reminder) []Reminder {
+	var duplicates []Reminder
+	seen := make(map[string]bool)
+	for _, want := range desired {
+		if seen[want.Key] {
+			duplicates = append(duplicates, want)
+			continue
+		}
+		seen[want.Key] = true
+	}
+	return duplicates
+}
+
+// isLegacy reports whether a reminder without a key was written by a version
+// that refilled the default list on every sync
+func isLegacy(r Reminder) bool {
+	return r.Key == "" && r.List == "" && legacyNotesRegex.MatchString(strings.TrimSpace(r.Notes))
+}
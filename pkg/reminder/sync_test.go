@@ -0,0 +1,192 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSync(t *testing.T) {
+	due := time.Date(2024, 3, 5, 16, 0, 0, 0, time.UTC)
+	backend := NewMemoryBackend()
+	manual, _ := backend.Add(Reminder{Title: "Created by hand"})
+	unchanged, _ := backend.Add(Reminder{Key: "k1", Title: "Same", Notes: "edited in app", Completed: true})
+	renamed, _ := backend.Add(Reminder{Key: "k2", Title: "Old title"})
+	backend.Add(Reminder{Key: "k3", Title: "No longer active"})
+	backend.Add(Reminder{Key: "k1", Title: "Same"}) // duplicate
+
+	result, err := Sync(backend, []Reminder{
+		{Key: "k1", Title: "Same", Notes: "generated"},
+		{Key: "k2", Title: "New title", Due: due},
+		{Key: "k4", Title: "Added", Notes: "generated"},
+		{Key: "k4", Title: "Added"}, // same task listed twice
+	})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	want := SyncResult{Added: 1, Updated: 1, Removed: 2, Unchanged: 1}
+	if *result != want {
+		t.Errorf("Sync() = %+v, want %+v", *result, want)
+	}
+
+	reminders, _ := backend.List()
+	byID := make(map[string]Reminder)
+	for _, r := range reminders {
+		byID[r.ID] = r
+	}
+	if len(reminders) != 4 {
+		t.Fatalf("got %d reminders, want 4: %+v", len(reminders), reminders)
+	}
+	if _, ok := byID[manual]; !ok {
+		t.Error("reminder without a key was removed")
+	}
+	if r := byID[unchanged]; r.Notes != "edited in app" || !r.Completed {
+		t.Errorf("unchanged reminder = %+v, want notes and completion preserved", r)
+	}
+	if r := byID[renamed]; r.Title != "New title" || !r.Due.Equal(due) || r.Key != "k2" {
+		t.Errorf("updated reminder = %+v", r)
+	}
+	if r := reminders[len(reminders)-1]; r.Key != "k4" || r.Notes != "generated" {
+		t.Errorf("added reminder = %+v", r)
+	}
+
+	if _, err := Sync(backend, []Reminder{{Title: "No key"}}); err == nil {
+		t.Error("Sync() should reject reminders without a key")
+	}
+}
+
+func TestSync_FlagAndNotes(t *testing.T) {
+	backend := NewMemoryBackend()
+	flagged, _ := backend.Add(Reminder{Key: "k1", Title: "Urgent", Notes: "Priority: Critical"})
+	moved, _ := backend.Add(Reminder{Key: "k2", Title: "Moved", Notes: "Priority: Low\n\nSource: todo.md:2"})
+	edited, _ := backend.Add(Reminder{Key: "k3", Title: "Edited", Notes: "Priority: Low\ncall first\n\nSource: todo.md:3"})
+
+	result, err := Sync(backend, []Reminder{
+		{Key: "k1", Title: "Urgent", Flagged: true, Notes: "Priority: Critical\n\nSource: todo.md:1"},
+		{Key: "k2", Title: "Moved", Notes: "Priority: Low\n\nSource: todo.md:5"},
+		{Key: "k3", Title: "Edited", Notes: "Priority: Low\n\nSource: todo.md:6"},
+	})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if want := (SyncResult{Updated: 2, Unchanged: 1}); *result != want {
+		t.Errorf("Sync() = %+v, want %+v", *result, want)
+	}
+
+	reminders, _ := backend.List()
+	byID := make(map[string]Reminder)
+	for _, r := range reminders {
+		byID[r.ID] = r
+	}
+	if r := byID[flagged]; !r.Flagged || r.Notes != "Priority: Critical\n\nSource: todo.md:1" {
+		t.Errorf("flagged reminder = %+v, want flag set and legacy notes replaced", r)
+	}
+	if r := byID[moved]; r.Notes != "Priority: Low\n\nSource: todo.md:5" {
+		t.Errorf("moved reminder notes = %q, want backlink updated", r.Notes)
+	}
+	if r := byID[edited]; r.Notes != "Priority: Low\ncall first\n\nSource: todo.md:3" {
+		t.Errorf("edited reminder notes = %q, want notes kept", r.Notes)
+	}
+}
+
+func TestSync_MigratesLegacyReminders(t *testing.T) {
+	backend := NewMemoryBackend()
+	manual, _ := backend.Add(Reminder{Title: "Created by hand", Notes: "call mom"})
+	adopted, _ := backend.Add(Reminder{Title: "A1 deploy", Notes: "Priority: Critical, Effort: 1"})
+	backend.Add(Reminder{Title: "A1 deploy", Notes: "Priority: Critical, Effort: 1"})       // second copy
+	backend.Add(Reminder{Title: "B2 no longer active", Notes: "Priority: High, Effort: 2"}) // stale
+	otherList, _ := backend.Add(Reminder{Title: "In another list", Notes: "Priority: None", List: "Home"})
+
+	desired := []Reminder{
+		{Key: "k1", Title: "A1 deploy", Notes: "Priority: Critical, Effort: 1\n\nSource: todo.md:3", Flagged: true},
+		{Key: "k2", Title: "New task", Notes: "Priority: None\n\nSource: todo.md:4"},
+	}
+	result, err := Sync(backend, desired)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	want := SyncResult{Added: 1, Updated: 1, Removed: 2}
+	if *result != want {
+		t.Errorf("Sync() = %+v, want %+v", *result, want)
+	}
+
+	reminders, _ := backend.List()
+	byID := make(map[string]Reminder)
+	for _, r := range reminders {
+		byID[r.ID] = r
+	}
+	if len(reminders) != 4 {
+		t.Fatalf("got %d reminders, want 4: %+v", len(reminders), reminders)
+	}
+	if r := byID[adopted]; r.Key != "k1" || r.Notes != desired[0].Notes || !r.Flagged {
+		t.Errorf("adopted reminder = %+v, want key, notes and flag of the task", r)
+	}
+	if _, ok := byID[manual]; !ok {
+		t.Error("reminder created by hand was removed")
+	}
+	if _, ok := byID[otherList]; !ok {
+		t.Error("reminder in another list was removed")
+	}
+
+	// the next sync finds everything keyed and in place
+	result, err = Sync(backend, desired)
+	if err != nil || *result != (SyncResult{Unchanged: 2}) {
+		t.Errorf("second Sync() = %+v, %v, want 2 unchanged", result, err)
+	}
+}
+
+func TestSync_RegeneratesUneditedNotes(t *testing.T) {
+	backend := NewMemoryBackend()
+	desired := []Reminder{
+		{Key: "k1", Title: "Generated", Notes: "Priority: Low\n\nSource: todo.md:1"},
+		{Key: "k2", Title: "Edited", Notes: "Priority: Low\n\nSource: todo.md:2"},
+	}
+	if _, err := Sync(backend, desired); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	reminders, _ := backend.List()
+	edited := reminders[1]
+	edited.Notes = "Priority: Low\ncall first\n\nSource: todo.md:2"
+	backend.Update(edited)
+
+	// priority and details changed in the todo file
+	desired[0].Notes = "Priority: High\n- check logs\n\nSource: todo.md:1"
+	desired[1].Notes = "Priority: High\n\nSource: todo.md:2"
+	result, err := Sync(backend, desired)
+	if err != nil || *result != (SyncResult{Updated: 1, Unchanged: 1}) {
+		t.Errorf("Sync() = %+v, %v, want 1 updated and 1 unchanged", result, err)
+	}
+
+	reminders, _ = backend.List()
+	if reminders[0].Notes != desired[0].Notes {
+		t.Errorf("generated notes = %q, want regenerated %q", reminders[0].Notes, desired[0].Notes)
+	}
+	if reminders[1].Notes != edited.Notes {
+		t.Errorf("edited notes = %q, want kept %q", reminders[1].Notes, edited.Notes)
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	desired := []Reminder{
+		{Key: "k1", Title: "Call Bob", Line: 2},
+		{Key: "k2", Title: "Write report", Line: 3},
+		{Key: "k1", Title: "Call Bob", Line: 7},
+	}
+
+	duplicates := Duplicates(desired)
+	if len(duplicates) != 1 || duplicates[0].Line != 7 {
+		t.Errorf("Duplicates() = %+v, want the task on line 7", duplicates)
+	}
+	if duplicates := Duplicates(desired[:2]); len(duplicates) != 0 {
+		t.Errorf("Duplicates() = %+v, want none", duplicates)
+	}
+
+	backend := NewMemoryBackend()
+	result, err := Sync(backend, desired)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if want := (SyncResult{Added: 2}); *result != want {
+		t.Errorf("Sync() = %+v, want %+v", *result, want)
+	}
+}
@@ -0,0 +1,216 @@
+// Package snapshot keeps timestamped copies of todo files before they are modified.
+// Snapshots are stored in a .taskmasterra/snapshots directory next to the todo
+// file and pruned by count and age, giving users without version control a
+// cheap way to undo a recordkeep or restore.
+package snapshot
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/utils"
+)
+
+// DirName is the snapshot directory, relative to the todo file's directory
+var DirName = filepath.Join(".taskmasterra", "snapshots")
+
+// timeLayout is used in snapshot file names; it sorts chronologically
+const timeLayout = "20060102T150405.000000000Z"
+
+// Snapshot is a saved copy of a todo file
+type Snapshot struct {
+	ID   string // file name without directory
+	Path string
+	Time time.Time
+	Size int64
+}
+
+// Manager handles snapshots of a single todo file
+type Manager struct {
+	Dir          string
+	OriginalPath string
+
+	// Retention; zero disables the limit. The newest snapshot is always kept.
+	KeepCount int
+	KeepAge   time.Duration
+}
+
+// NewManager creates a snapshot manager for a todo file
+func NewManager(filePath string) *Manager {
+	return &Manager{
+		Dir:          filepath.Join(filepath.Dir(filePath), DirName),
+		OriginalPath: filePath,
+	}
+}
+
+// prefix returns the file name prefix shared by all snapshots of the todo file
+func (m *Manager) prefix() string {
+	return filepath.Base(m.OriginalPath) + "."
+}
+
+// Save copies the current todo file into the snapshot directory and prunes
+// old snapshots. A missing todo file is not an error and creates no snapshot.
+func (m *Manager) Save() (*Snapshot, error) {
+	source, err := os.Open(m.OriginalPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open '%s' for snapshot: %w", m.OriginalPath, err)
+	}
+	defer source.Close()
+
+	if err := utils.EnsureDirectoryExists(m.Dir); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	now := time.Now().UTC()
+	id := m.prefix() + now.Format(timeLayout)
+	path := filepath.Join(m.Dir, id)
+
+	target, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, utils.DefaultFilePermission)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot '%s': %w", path, err)
+	}
+	size, err := io.Copy(target, source)
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to write snapshot '%s': %w", path, err)
+	}
+
+	if err := m.Prune(now); err != nil {
+		return nil, err
+	}
+
+	return &Snapshot{ID: id, Path: path, Time: now, Size: size}, nil
+}
+
+// List returns the snapshots of the todo file, newest first
+func (m *Manager) List() ([]Snapshot, error) {
+	dirEntries, err := os.ReadDir(m.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory '%s': %w", m.Dir, err)
+	}
+
+	var snapshots []Snapshot
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || !strings.HasPrefix(name, m.prefix()) {
+			continue
+		}
+		stamp, err := time.Parse(timeLayout, strings.TrimPrefix(name, m.prefix()))
+		if err != nil {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat snapshot '%s': %w", name, err)
+		}
+		snapshots = append(snapshots, Snapshot{
+			ID:   name,
+			Path: filepath.Join(m.Dir, name),
+			Time: stamp,
+			Size: info.Size(),
+		})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.After(snapshots[j].Time)
+	})
+	return snapshots, nil
+}
+
+// Prune removes snapshots beyond KeepCount or older than KeepAge relative to now.
+// The newest snapshot is never removed.
+func (m *Manager) Prune(now time.Time) error {
+	snapshots, err := m.List()
+	if err != nil {
+		return err
+	}
+
+	for i, snap := range snapshots {
+		if i == 0 {
+			continue
+		}
+		tooMany := m.KeepCount > 0 && i >= m.KeepCount
+		tooOld := m.KeepAge > 0 && now.Sub(snap.Time) > m.KeepAge
+		if tooMany || tooOld {
+			if err := os.Remove(snap.Path); err != nil {
+				return fmt.Errorf("failed to remove old snapshot '%s': %w", snap.Path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Find returns the snapshot matching a list number (1 is the newest) or a
+// unique prefix of its ID or timestamp
+func (m *Manager) Find(ref string) (*Snapshot, error) {
+	snapshots, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no snapshots found for '%s'", m.OriginalPath)
+	}
+
+	index, indexErr := strconv.Atoi(ref)
+	if indexErr == nil && index >= 1 && index <= len(snapshots) {
+		return &snapshots[index-1], nil
+	}
+
+	var matches []Snapshot
+	for _, snap := range snapshots {
+		if strings.HasPrefix(snap.ID, ref) || strings.HasPrefix(strings.TrimPrefix(snap.ID, m.prefix()), ref) {
+			matches = append(matches, snap)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		if indexErr == nil {
+			return nil, fmt.Errorf("snapshot %d does not exist (have %d)", index, len(snapshots))
+		}
+		return nil, fmt.Errorf("no snapshot matches '%s'", ref)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("'%s' matches %d snapshots, be more specific", ref, len(matches))
+	}
+}
+
+// Restore replaces the todo file with a snapshot. The current todo file is
+// snapshotted first so that the restore itself can be undone.
+func (m *Manager) Restore(ref string) (*Snapshot, error) {
+	snap, err := m.Find(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(snap.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot '%s': %w", snap.Path, err)
+	}
+
+	if _, err := m.Save(); err != nil {
+		return nil, fmt.Errorf("failed to snapshot current file before restore: %w", err)
+	}
+
+	if err := utils.WriteFileContent(m.OriginalPath, string(content)); err != nil {
+		return nil, fmt.Errorf("failed to restore snapshot to '%s': %w", m.OriginalPath, err)
+	}
+
+	return snap, nil
+}
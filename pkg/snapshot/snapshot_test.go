@@ -0,0 +1,156 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupTodo(t *testing.T, content string) (string, func()) {
+	tmpDir, err := os.MkdirTemp("", "snapshot-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	todoPath := filepath.Join(tmpDir, "todo.md")
+	if err := os.WriteFile(todoPath, []byte(content), 0644); err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatalf("Failed to write todo file: %v", err)
+	}
+	return todoPath, func() { os.RemoveAll(tmpDir) }
+}
+
+func TestSaveAndList(t *testing.T) {
+	todoPath, cleanup := setupTodo(t, "- [ ] first version\n")
+	defer cleanup()
+
+	sm := NewManager(todoPath)
+	if !strings.HasSuffix(sm.Dir, filepath.Join(".taskmasterra", "snapshots")) {
+		t.Errorf("Unexpected snapshot directory %s", sm.Dir)
+	}
+
+	first, err := sm.Save()
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := os.WriteFile(todoPath, []byte("- [ ] second version\n"), 0644); err != nil {
+		t.Fatalf("Failed to update todo file: %v", err)
+	}
+	if _, err := sm.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Snapshots of other files in the same directory are ignored
+	other := NewManager(filepath.Join(filepath.Dir(todoPath), "other.md"))
+	if err := os.WriteFile(other.OriginalPath, []byte("other"), 0644); err != nil {
+		t.Fatalf("Failed to write other file: %v", err)
+	}
+	if _, err := other.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	snapshots, err := sm.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("Expected 2 snapshots, got %d", len(snapshots))
+	}
+	if snapshots[1].ID != first.ID {
+		t.Errorf("Snapshots not listed newest first: %v", snapshots)
+	}
+	content, _ := os.ReadFile(snapshots[0].Path)
+	if string(content) != "- [ ] second version\n" {
+		t.Errorf("Newest snapshot content = %q", content)
+	}
+}
+
+func TestSaveMissingFile(t *testing.T) {
+	sm := NewManager(filepath.Join(os.TempDir(), "does-not-exist", "todo.md"))
+	snap, err := sm.Save()
+	if err != nil || snap != nil {
+		t.Errorf("Save() of missing file = %v, %v, want nil, nil", snap, err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	todoPath, cleanup := setupTodo(t, "- [ ] task\n")
+	defer cleanup()
+
+	sm := NewManager(todoPath)
+	if err := os.MkdirAll(sm.Dir, 0755); err != nil {
+		t.Fatalf("Failed to create snapshot dir: %v", err)
+	}
+	now := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+	for _, age := range []time.Duration{0, time.Hour, 48 * time.Hour, 72 * time.Hour, 96 * time.Hour} {
+		name := sm.prefix() + now.Add(-age).Format(timeLayout)
+		if err := os.WriteFile(filepath.Join(sm.Dir, name), []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to write snapshot: %v", err)
+		}
+	}
+
+	sm.KeepCount = 4
+	sm.KeepAge = 60 * time.Hour
+	if err := sm.Prune(now); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	snapshots, _ := sm.List()
+	if len(snapshots) != 3 {
+		t.Errorf("Expected 3 snapshots after prune, got %d", len(snapshots))
+	}
+
+	// The newest snapshot survives even if it is too old
+	sm.KeepCount = 0
+	sm.KeepAge = time.Minute
+	if err := sm.Prune(now.Add(24 * time.Hour)); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	snapshots, _ = sm.List()
+	if len(snapshots) != 1 || !snapshots[0].Time.Equal(now) {
+		t.Errorf("Expected only the newest snapshot to remain, got %v", snapshots)
+	}
+}
+
+func TestFindAndRestore(t *testing.T) {
+	todoPath, cleanup := setupTodo(t, "original\n")
+	defer cleanup()
+
+	sm := NewManager(todoPath)
+	original, err := sm.Save()
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := os.WriteFile(todoPath, []byte("broken by recordkeep\n"), 0644); err != nil {
+		t.Fatalf("Failed to update todo file: %v", err)
+	}
+
+	if _, err := sm.Find("7"); err == nil {
+		t.Error("Expected error for out of range snapshot number")
+	}
+	if _, err := sm.Find("nope"); err == nil {
+		t.Error("Expected error for unknown snapshot ID")
+	}
+	found, err := sm.Find(strings.TrimPrefix(original.ID, "todo.md.")[:8])
+	if err != nil || found.ID != original.ID {
+		t.Errorf("Find by timestamp prefix = %v, %v", found, err)
+	}
+
+	if _, err := sm.Restore("1"); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	content, _ := os.ReadFile(todoPath)
+	if string(content) != "original\n" {
+		t.Errorf("Restored content = %q, want %q", content, "original\n")
+	}
+
+	// The overwritten version was snapshotted and can be restored again
+	snapshots, _ := sm.List()
+	if len(snapshots) != 2 {
+		t.Fatalf("Expected 2 snapshots after restore, got %d", len(snapshots))
+	}
+	latest, _ := os.ReadFile(snapshots[0].Path)
+	if string(latest) != "broken by recordkeep\n" {
+		t.Errorf("Pre-restore snapshot content = %q", latest)
+	}
+}
@@ -0,0 +1,136 @@
+// Package standup builds a daily "Yesterday / Today / Blocked" report from the
+// journal and the current todo file, ready to paste into a team thread.
+package standup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+// Output formats supported by Render
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+)
+
+// Item is a task shown in the report
+type Item struct {
+	ID    string
+	Title string
+	Done  bool
+}
+
+// Report holds the three standup sections
+type Report struct {
+	Day       time.Time // the working day Yesterday covers
+	Yesterday []Item
+	Today     []Item
+	Blocked   []Item
+}
+
+// LastWorkingDay returns midnight of the last weekday before now in now's location.
+// On Mondays this is the previous Friday.
+func LastWorkingDay(now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for {
+		day = day.AddDate(0, 0, -1)
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			return day
+		}
+	}
+}
+
+// Build creates a report from journal entries and the lines of the todo file.
+// Yesterday lists the tasks journaled on the last working day before now,
+// Today the active (!!) tasks and Blocked the tasks with a [b] status.
+// A task journaled several times is listed once.
+func Build(entries []journal.Entry, todoLines []string, now time.Time) *Report {
+	day := LastWorkingDay(now)
+	report := &Report{Day: day}
+	end := day.AddDate(0, 0, 1)
+
+	seen := make(map[string]int)
+	for _, entry := range entries {
+		if entry.Timestamp.Before(day) || !entry.Timestamp.Before(end) {
+			continue
+		}
+		item := newItem(entry.Line)
+		if index, ok := seen[item.ID]; ok {
+			// Keep the task once, done if any of its entries was completed
+			report.Yesterday[index].Done = report.Yesterday[index].Done || item.Done
+			continue
+		}
+		seen[item.ID] = len(report.Yesterday)
+		report.Yesterday = append(report.Yesterday, item)
+	}
+
+	for _, line := range todoLines {
+		switch {
+		case task.IsActive(line):
+			report.Today = append(report.Today, newItem(line))
+		case task.IsBlocked(line):
+			report.Blocked = append(report.Blocked, newItem(line))
+		}
+	}
+
+	return report
+}
+
+// newItem creates a report item from a task line
+func newItem(line string) Item {
+	item := Item{ID: task.ID(line), Title: strings.TrimSpace(line)}
+	info := task.ParseTaskInfo(strings.TrimLeft(line, " \t"))
+	if info != nil {
+		item.Title = strings.TrimSpace(strings.TrimPrefix(info.Title, "!!"))
+		item.Done = strings.EqualFold(info.Status, "x")
+	}
+	return item
+}
+
+// Render formats the report as markdown or plain text
+func Render(report *Report, format string) (string, error) {
+	var header, bullet string
+	switch format {
+	case FormatMarkdown, "md", "":
+		header, bullet = "**%s**\n", "- "
+	case FormatText:
+		header, bullet = "%s:\n", "  * "
+	default:
+		return "", fmt.Errorf("unknown output format '%s' (use markdown or text)", format)
+	}
+
+	sections := []struct {
+		title string
+		items []Item
+		empty string
+	}{
+		{fmt.Sprintf("Yesterday (%s)", report.Day.Format("Mon Jan 2")), report.Yesterday, "Nothing journaled"},
+		{"Today", report.Today, "No active tasks"},
+		{"Blocked", report.Blocked, "Nothing blocked"},
+	}
+
+	var output strings.Builder
+	for i, section := range sections {
+		if i > 0 {
+			output.WriteString("\n")
+		}
+		output.WriteString(fmt.Sprintf(header, section.title))
+		if len(section.items) == 0 {
+			output.WriteString(bullet + section.empty + "\n")
+			continue
+		}
+		for _, item := range section.items {
+			output.WriteString(bullet + item.Title)
+			if item.Done {
+				output.WriteString(" (done)")
+			}
+			output.WriteString("\n")
+		}
+	}
+
+	return output.String(), nil
+}
@@ -0,0 +1,86 @@
+package standup
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+)
+
+func TestLastWorkingDay(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"Tuesday", time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC), time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{"Monday", time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"Sunday", time.Date(2024, 3, 3, 9, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LastWorkingDay(tt.now); !got.Equal(tt.want) {
+				t.Errorf("LastWorkingDay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildAndRender(t *testing.T) {
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC) // Monday
+	entries := []journal.Entry{
+		{Timestamp: time.Date(2024, 3, 1, 17, 0, 0, 0, time.UTC), Line: "- [X] Ship release"},
+		{Timestamp: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), Line: "- [W] !! Ship release"},
+		{Timestamp: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), Line: "- [W] Review PR A2"},
+		{Timestamp: time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC), Line: "- [W] Too old"},
+		{Timestamp: time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC), Line: "- [W] Already today"},
+	}
+	todo := []string{
+		"# Work",
+		"- [ ] !! Write docs",
+		"- [b] Deploy, waiting on ops",
+		"  - [B] Blocked subtask",
+		"- [ ] Someday",
+	}
+
+	report := Build(entries, todo, now)
+
+	if len(report.Yesterday) != 2 {
+		t.Fatalf("Yesterday has %d items, want 2: %+v", len(report.Yesterday), report.Yesterday)
+	}
+	if report.Yesterday[0].Title != "Ship release" || !report.Yesterday[0].Done {
+		t.Errorf("Yesterday[0] = %+v, want done 'Ship release'", report.Yesterday[0])
+	}
+	if len(report.Today) != 1 || report.Today[0].Title != "Write docs" {
+		t.Errorf("Today = %+v, want 'Write docs'", report.Today)
+	}
+	if len(report.Blocked) != 2 {
+		t.Errorf("Blocked has %d items, want 2: %+v", len(report.Blocked), report.Blocked)
+	}
+
+	markdown, err := Render(report, FormatMarkdown)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{"**Yesterday (Fri Mar 1)**", "- Ship release (done)", "- Review PR A2", "**Today**\n- Write docs", "**Blocked**\n- Deploy, waiting on ops"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("markdown missing %q:\n%s", want, markdown)
+		}
+	}
+
+	text, err := Render(&Report{Day: now}, FormatText)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{"Today:\n  * No active tasks", "Blocked:\n  * Nothing blocked"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text missing %q:\n%s", want, text)
+		}
+	}
+
+	if _, err := Render(report, "html"); err == nil {
+		t.Error("Render() expected error for unknown format")
+	}
+}
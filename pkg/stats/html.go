@@ -0,0 +1,335 @@
+package stats
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+)
+
+// HistoryWeeks is the number of weeks in the archive chart of the HTML report
+const HistoryWeeks = 12
+
+// Chart layout in pixels
+const (
+	chartWidth     = 420
+	chartLabelSize = 100
+	chartBarHeight = 18
+	chartRowHeight = 26
+)
+
+// htmlBar is a bar of a horizontal bar chart
+type htmlBar struct {
+	Label  string
+	Count  int
+	Color  string
+	Y      int // top of the bar
+	TextY  int // baseline of the label and count
+	Width  int
+	CountX int
+}
+
+// htmlChart is an inline SVG bar chart
+type htmlChart struct {
+	Title  string
+	Height int
+	Bars   []htmlBar
+}
+
+// htmlTask is a row of the task table
+type htmlTask struct {
+	Section  string
+	State    string
+	Priority string
+	Effort   int
+	Title    string
+	Tags     string
+	Date     string
+	Subtask  bool
+}
+
+// htmlArchived is a row of the archive history table
+type htmlArchived struct {
+	Archived string
+	Section  string
+	Title    string
+	Priority string
+	Effort   int
+}
+
+// htmlReport is the data of the HTML report template
+type htmlReport struct {
+	Generated      string
+	Total          int
+	CompletionRate string
+	StatusChart    htmlChart
+	PriorityChart  htmlChart
+	HistoryChart   htmlChart
+	States         []string
+	Tasks          []htmlTask
+	Archive        []htmlArchived
+}
+
+// Status and priority names with their chart colors, in chart order
+var (
+	htmlStates = []struct{ name, color string }{
+		{"open", "#90a4ae"},
+		{"active", "#1e88e5"},
+		{"worked", "#fb8c00"},
+		{"blocked", "#e53935"},
+		{"completed", "#43a047"},
+	}
+	htmlPriorities = []struct{ name, color string }{
+		{task.PriorityCritical.String(), "#c62828"},
+		{task.PriorityHigh.String(), "#ef6c00"},
+		{task.PriorityMedium.String(), "#f9a825"},
+		{task.PriorityLow.String(), "#2e7d32"},
+		{task.PriorityNone.String(), "#90a4ae"},
+	}
+)
+
+// GenerateHTMLReport renders task statistics as a self-contained HTML page
+// with no external assets, ready to publish on a static site: status and
+// priority charts as inline SVG, a table of every task with a text and state
+// filter, and the archive history as a chart of completions per week over the
+// last HistoryWeeks weeks and a table of archived tasks, newest first.
+func GenerateHTMLReport(stats *TaskStats) (string, error) {
+	open := stats.TotalTasks - stats.CompletedTasks - stats.ActiveTasks - stats.BlockedTasks - stats.WorkedTasks
+	counts := map[string]int{
+		"open":      open,
+		"active":    stats.ActiveTasks,
+		"worked":    stats.WorkedTasks,
+		"blocked":   stats.BlockedTasks,
+		"completed": stats.CompletedTasks,
+	}
+
+	data := htmlReport{
+		Generated:      stats.Date.Format("2006-01-02 15:04:05"),
+		Total:          stats.TotalTasks,
+		CompletionRate: fmt.Sprintf("%.1f%%", percentage(stats.CompletedTasks, stats.TotalTasks)),
+	}
+
+	var statusBars, priorityBars []htmlBar
+	for _, state := range htmlStates {
+		statusBars = append(statusBars, htmlBar{Label: state.name, Count: counts[state.name], Color: state.color})
+		data.States = append(data.States, state.name)
+	}
+	for _, priority := range htmlPriorities {
+		priorityBars = append(priorityBars, htmlBar{Label: priority.name, Count: stats.PriorityStats[priority.name], Color: priority.color})
+	}
+	data.StatusChart = newHTMLChart("Tasks by status", statusBars)
+	data.PriorityChart = newHTMLChart("Tasks by priority", priorityBars)
+
+	for _, t := range stats.Tasks {
+		row := htmlTask{
+			Section:  t.Section,
+			State:    htmlState(t),
+			Priority: t.Priority.String(),
+			Effort:   t.Effort,
+			Title:    t.Title,
+			Tags:     strings.Join(t.Tags, " "),
+			Subtask:  t.Subtask,
+		}
+		if t.Date != nil {
+			row.Date = t.Date.String()
+		}
+		data.Tasks = append(data.Tasks, row)
+	}
+
+	data.HistoryChart = newHTMLChart("Tasks archived per week", weeklyBars(stats.Archive, stats.Date))
+	for _, entry := range sortedNewestFirst(stats.Archive) {
+		row := htmlArchived{
+			Archived: entry.Timestamp.Format("2006-01-02 15:04"),
+			Section:  entry.Section,
+			Title:    task.CleanTitle(entry.Line),
+		}
+		if info := task.ParseTaskInfo(strings.TrimLeft(entry.Line, " \t")); info != nil {
+			row.Priority = info.Priority.String()
+			row.Effort = info.Effort
+		}
+		data.Archive = append(data.Archive, row)
+	}
+
+	var b strings.Builder
+	if err := htmlTemplate.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return b.String(), nil
+}
+
+// newHTMLChart lays out bars scaled to the largest count
+func newHTMLChart(title string, bars []htmlBar) htmlChart {
+	max := 0
+	for _, bar := range bars {
+		if bar.Count > max {
+			max = bar.Count
+		}
+	}
+	barSpace := chartWidth - chartLabelSize - 40 // room for the count after the bar
+	for i := range bars {
+		bars[i].Y = i*chartRowHeight + 4
+		bars[i].TextY = bars[i].Y + chartBarHeight - 4
+		if max > 0 {
+			bars[i].Width = bars[i].Count * barSpace / max
+		}
+		bars[i].CountX = chartLabelSize + bars[i].Width + 6
+	}
+	return htmlChart{Title: title, Height: len(bars)*chartRowHeight + 8, Bars: bars}
+}
+
+// weeklyBars counts archived tasks per week, starting on Mondays, for the
+// last HistoryWeeks weeks up to now
+func weeklyBars(archive []journal.Entry, now time.Time) []htmlBar {
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	first := monday.AddDate(0, 0, -7*(HistoryWeeks-1))
+
+	bars := make([]htmlBar, HistoryWeeks)
+	for i := range bars {
+		bars[i] = htmlBar{Label: first.AddDate(0, 0, 7*i).Format("Jan 2"), Color: "#43a047"}
+	}
+	for _, entry := range archive {
+		if entry.Timestamp.Before(first) {
+			continue
+		}
+		// rounded so a daylight saving change does not shift the day
+		y, m, d := entry.Timestamp.In(now.Location()).Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+		days := int(math.Round(day.Sub(first).Hours() / 24))
+		if week := days / 7; week < HistoryWeeks {
+			bars[week].Count++
+		}
+	}
+	return bars
+}
+
+// htmlState returns the state of a task as counted by AnalyzeFile
+func htmlState(t task.Task) string {
+	switch {
+	case strings.EqualFold(t.Status, "x"):
+		return "completed"
+	case t.Active:
+		return "active"
+	case strings.EqualFold(t.Status, "b"):
+		return "blocked"
+	case strings.EqualFold(t.Status, "w"):
+		return "worked"
+	default:
+		return "open"
+	}
+}
+
+// htmlTemplate is the page written by GenerateHTMLReport; "chart" renders an htmlChart
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Task Statistics Report</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 2rem; color: #263238; }
+h1 { margin-bottom: 0; }
+.generated { color: #607d8b; margin-top: 0.25rem; }
+.summary { display: flex; gap: 2rem; margin: 1rem 0; }
+.summary div { font-size: 1.5rem; font-weight: bold; }
+.summary span { display: block; font-size: 0.8rem; font-weight: normal; color: #607d8b; }
+.charts { display: flex; flex-wrap: wrap; gap: 2rem; }
+svg text { font-size: 12px; fill: #263238; }
+table { border-collapse: collapse; width: 100%; margin-top: 0.5rem; }
+th, td { text-align: left; padding: 0.3rem 0.6rem; border-bottom: 1px solid #eceff1; }
+th { background: #f5f7f8; }
+td.subtask { padding-left: 1.8rem; }
+.state { border-radius: 3px; padding: 0 0.4rem; color: #fff; font-size: 0.85rem; }
+.state-open { background: #90a4ae; }
+.state-active { background: #1e88e5; }
+.state-worked { background: #fb8c00; }
+.state-blocked { background: #e53935; }
+.state-completed { background: #43a047; }
+.filters { display: flex; gap: 0.5rem; align-items: center; }
+</style>
+</head>
+<body>
+<h1>Task Statistics Report</h1>
+<p class="generated">Generated: {{.Generated}}</p>
+<div class="summary">
+<div>{{.Total}}<span>tasks</span></div>
+<div>{{.CompletionRate}}<span>completed</span></div>
+<div>{{len .Archive}}<span>archived</span></div>
+</div>
+
+<div class="charts">
+{{template "chart" .StatusChart}}
+{{template "chart" .PriorityChart}}
+</div>
+
+<h2>Tasks</h2>
+<div class="filters">
+<input id="filter" type="search" placeholder="Filter by title, section or tag">
+<select id="state">
+<option value="">all states</option>
+{{- range .States}}
+<option value="{{.}}">{{.}}</option>
+{{- end}}
+</select>
+<span><span id="shown">{{len .Tasks}}</span> of {{len .Tasks}} shown</span>
+</div>
+<table id="tasks">
+<thead><tr><th>Section</th><th>State</th><th>Priority</th><th>Effort</th><th>Title</th><th>Tags</th><th>Date</th></tr></thead>
+<tbody>
+{{- range .Tasks}}
+<tr data-state="{{.State}}"><td>{{.Section}}</td><td><span class="state state-{{.State}}">{{.State}}</span></td><td>{{.Priority}}</td><td>{{.Effort}}</td><td{{if .Subtask}} class="subtask"{{end}}>{{.Title}}</td><td>{{.Tags}}</td><td>{{.Date}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+
+<h2>Archive History</h2>
+{{template "chart" .HistoryChart}}
+{{- if .Archive}}
+<table id="archive">
+<thead><tr><th>Archived</th><th>Section</th><th>Priority</th><th>Effort</th><th>Title</th></tr></thead>
+<tbody>
+{{- range .Archive}}
+<tr><td>{{.Archived}}</td><td>{{.Section}}</td><td>{{.Priority}}</td><td>{{.Effort}}</td><td>{{.Title}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}
+<p>No archived tasks.</p>
+{{- end}}
+
+<script>
+(function () {
+  var filter = document.getElementById("filter");
+  var state = document.getElementById("state");
+  function apply() {
+    var query = filter.value.toLowerCase();
+    var shown = 0;
+    document.querySelectorAll("#tasks tbody tr").forEach(function (row) {
+      var show = (!state.value || row.dataset.state === state.value) &&
+        row.textContent.toLowerCase().indexOf(query) !== -1;
+      row.hidden = !show;
+      if (show) shown++;
+    });
+    document.getElementById("shown").textContent = shown;
+  }
+  filter.addEventListener("input", apply);
+  state.addEventListener("change", apply);
+})();
+</script>
+</body>
+</html>
+{{define "chart"}}<figure>
+<figcaption>{{.Title}}</figcaption>
+<svg xmlns="http://www.w3.org/2000/svg" width="` + fmt.Sprint(chartWidth) + `" height="{{.Height}}" role="img" aria-label="{{.Title}}">
+{{- range .Bars}}
+<text x="0" y="{{.TextY}}">{{.Label}}</text>
+<rect x="` + fmt.Sprint(chartLabelSize) + `" y="{{.Y}}" width="{{.Width}}" height="` + fmt.Sprint(chartBarHeight) + `" fill="{{.Color}}"></rect>
+<text x="{{.CountX}}" y="{{.TextY}}">{{.Count}}</text>
+{{- end}}
+</svg>
+</figure>{{end}}`))
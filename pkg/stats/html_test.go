@@ -0,0 +1,106 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+)
+
+func TestGenerateHTMLReport(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "stats-html-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	testContent := `## Work
+- [ ] !! A1 Ship <script>alert(1)</script> release #launch
+  - [x] C2 write notes
+- [b] B3 Wait for review
+## Home
+- [w] Water plants <2024-03-09 Sat>
+`
+	testFile := filepath.Join(tmpDir, "test.md")
+	if err := os.WriteFile(testFile, []byte(testContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	stats, err := AnalyzeFile(testFile)
+	if err != nil {
+		t.Fatalf("AnalyzeFile failed: %v", err)
+	}
+	stats.Date = time.Date(2024, 3, 13, 10, 0, 0, 0, time.UTC) // a Wednesday
+	stats.Archive = []journal.Entry{
+		{Timestamp: time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC), Line: "- [x] D5 Renew passport", Section: "Travel"},
+		{Timestamp: time.Date(2024, 3, 12, 9, 0, 0, 0, time.UTC), Line: "- [x] Pay rent"},
+		{Timestamp: time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC), Line: "- [x] Too old for the chart"},
+	}
+
+	report, err := GenerateHTMLReport(stats)
+	if err != nil {
+		t.Fatalf("GenerateHTMLReport failed: %v", err)
+	}
+
+	expectedContent := []string{
+		"<!DOCTYPE html>",
+		"Generated: 2024-03-13 10:00:00",
+		`aria-label="Tasks by status"`,
+		`aria-label="Tasks by priority"`,
+		`aria-label="Tasks archived per week"`,
+		`<text x="0" y="18">open</text>`,
+		`<tr data-state="active"><td>Work</td>`,
+		`<td class="subtask">write notes</td>`,
+		`<tr data-state="blocked">`,
+		`<tr data-state="worked"><td>Home</td>`,
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+		"<td>&lt;2024-03-09 Sat&gt;</td>",
+		"<tr><td>2024-03-12 09:00</td><td></td><td>None</td><td>0</td><td>Pay rent</td></tr>",
+		"<tr><td>2024-03-11 09:00</td><td>Travel</td><td>Low</td><td>5</td><td>Renew passport</td></tr>",
+		`<text x="0" y="304">Mar 11</text>`,
+		"<td>Too old for the chart</td>",
+		`id="filter"`,
+	}
+	for _, expected := range expectedContent {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Report does not contain %q", expected)
+		}
+	}
+
+	// self-contained: no external scripts, styles or images
+	for _, external := range []string{"<script src", "<link", "<img", "@import"} {
+		if strings.Contains(report, external) {
+			t.Errorf("Report references an external asset: %q", external)
+		}
+	}
+	if strings.Contains(report, "<script>alert(1)") {
+		t.Error("Report does not escape task titles")
+	}
+}
+
+func TestWeeklyBars(t *testing.T) {
+	now := time.Date(2024, 3, 13, 10, 0, 0, 0, time.UTC)
+	archive := []journal.Entry{
+		{Timestamp: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},  // this week's Monday
+		{Timestamp: time.Date(2024, 3, 10, 23, 0, 0, 0, time.UTC)}, // last week's Sunday
+		{Timestamp: time.Date(2023, 12, 25, 8, 0, 0, 0, time.UTC)}, // first week of the chart
+		{Timestamp: time.Date(2023, 12, 24, 8, 0, 0, 0, time.UTC)}, // before the chart
+	}
+
+	bars := weeklyBars(archive, now)
+	if len(bars) != HistoryWeeks {
+		t.Fatalf("got %d bars, want %d", len(bars), HistoryWeeks)
+	}
+	if bars[0].Label != "Dec 25" || bars[HistoryWeeks-1].Label != "Mar 11" {
+		t.Errorf("labels = %q .. %q, want Dec 25 .. Mar 11", bars[0].Label, bars[HistoryWeeks-1].Label)
+	}
+	counts := map[int]int{0: 1, HistoryWeeks - 2: 1, HistoryWeeks - 1: 1}
+	for i, bar := range bars {
+		if bar.Count != counts[i] {
+			t.Errorf("week %d count = %d, want %d", i, bar.Count, counts[i])
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+	"github.com/robertarles/taskmasterra/v2/pkg/task"
+	"github.com/robertarles/taskmasterra/v2/pkg/utils"
+)
+
+// DefaultStaleDays is the number of days without a journal entry after which
+// an open task is considered stale
+const DefaultStaleDays = 14
+
+// ReviewOptions controls the period and thresholds of a review
+type ReviewOptions struct {
+	From      time.Time // inclusive start of the period
+	Until     time.Time // exclusive end of the period
+	StaleDays int       // zero selects DefaultStaleDays
+	Timestamp journal.TimestampFormat
+}
+
+// WeekOptions returns options for the seven days up to and including now's day
+func WeekOptions(now time.Time, format journal.TimestampFormat) ReviewOptions {
+	loc := format.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	return ReviewOptions{
+		From:      today.AddDate(0, 0, -6),
+		Until:     today.AddDate(0, 0, 1),
+		StaleDays: DefaultStaleDays,
+		Timestamp: format,
+	}
+}
+
+// ReviewItem is a task listed in a review
+type ReviewItem struct {
+	ID     string
+	Line   string
+	Effort int
+	Time   time.Time // completion, last touch or blocked-since time; zero if unknown
+	Count  int       // number of journal entries in the period, for touched tasks
+}
+
+// Review summarizes the work recorded in the journal and archive over a period
+type Review struct {
+	From            time.Time
+	Until           time.Time
+	Now             time.Time
+	StaleDays       int
+	Completed       []ReviewItem // archived in the period
+	Touched         []ReviewItem // journaled in the period
+	Stale           []ReviewItem // open tasks not journaled for StaleDays
+	Blocked         []ReviewItem // blocked tasks with the time they were first journaled as blocked
+	EffortDelivered int
+}
+
+// AnalyzeHistory builds a review of a todo file from its journal and archive.
+// now is used to measure how long tasks have been stale or blocked.
+func AnalyzeHistory(filePath string, opts ReviewOptions, now time.Time) (*Review, error) {
+	content, err := utils.ReadFileContent(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file '%s': %w", filePath, err)
+	}
+
+	jm := journal.NewManager(filePath)
+	// The whole journal is needed to find when stale and blocked tasks were last touched
+	journalEntries, err := jm.ReadJournal(opts.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal for '%s': %w", filePath, err)
+	}
+	archiveEntries, err := jm.RecentArchive(opts.Timestamp, opts.From)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive for '%s': %w", filePath, err)
+	}
+
+	return BuildReview(strings.Split(content, "\n"), journalEntries, archiveEntries, opts, now), nil
+}
+
+// BuildReview builds a review from the lines of a todo file and its journal and archive entries
+func BuildReview(todoLines []string, journalEntries, archiveEntries []journal.Entry, opts ReviewOptions, now time.Time) *Review {
+	review := &Review{From: opts.From, Until: opts.Until, Now: now, StaleDays: opts.StaleDays}
+	if review.StaleDays <= 0 {
+		review.StaleDays = DefaultStaleDays
+	}
+
+	for _, entry := range archiveEntries {
+		if !inPeriod(entry.Timestamp, opts) || isIndented(entry.Line) {
+			continue
+		}
+		effort := task.ParseEffort(entry.Line)
+		review.Completed = append(review.Completed, ReviewItem{
+			ID:     task.ID(entry.Line),
+			Line:   strings.TrimSpace(entry.Line),
+			Effort: effort,
+			Time:   entry.Timestamp,
+		})
+		review.EffortDelivered += effort
+	}
+
+	// Journal entries per task, newest first
+	byID := make(map[string][]journal.Entry)
+	touched := make(map[string]int)
+	for _, entry := range sortedNewestFirst(journalEntries) {
+		id := task.ID(entry.Line)
+		byID[id] = append(byID[id], entry)
+		if !inPeriod(entry.Timestamp, opts) {
+			continue
+		}
+		if index, ok := touched[id]; ok {
+			review.Touched[index].Count++
+			continue
+		}
+		touched[id] = len(review.Touched)
+		review.Touched = append(review.Touched, ReviewItem{
+			ID:     id,
+			Line:   strings.TrimSpace(entry.Line),
+			Effort: task.ParseEffort(entry.Line),
+			Time:   entry.Timestamp,
+			Count:  1,
+		})
+	}
+
+	staleBefore := now.AddDate(0, 0, -review.StaleDays)
+	for _, line := range todoLines {
+		if !task.IsTask(line) || task.IsCompleted(line) {
+			continue
+		}
+		id := task.ID(line)
+		item := ReviewItem{ID: id, Line: strings.TrimSpace(line), Effort: task.ParseEffort(line)}
+		history := byID[id]
+
+		if task.IsBlocked(line) {
+			item.Time = blockedSince(history)
+			review.Blocked = append(review.Blocked, item)
+			continue
+		}
+
+		if task.IsActive(line) {
+			continue
+		}
+		if len(history) > 0 {
+			item.Time = history[0].Timestamp
+		}
+		if item.Time.Before(staleBefore) {
+			review.Stale = append(review.Stale, item)
+		}
+	}
+
+	// Longest-blocked and longest-stale first; never-touched tasks sort first
+	sort.SliceStable(review.Blocked, func(i, j int) bool { return review.Blocked[i].Time.Before(review.Blocked[j].Time) })
+	sort.SliceStable(review.Stale, func(i, j int) bool { return review.Stale[i].Time.Before(review.Stale[j].Time) })
+
+	return review
+}
+
+// blockedSince returns the oldest journal entry of the most recent run of
+// blocked entries. history must be ordered newest first.
+func blockedSince(history []journal.Entry) time.Time {
+	var since time.Time
+	for _, entry := range history {
+		if !task.IsBlocked(entry.Line) {
+			break
+		}
+		since = entry.Timestamp
+	}
+	return since
+}
+
+// GenerateReviewReport renders a review as a markdown document
+func GenerateReviewReport(review *Review) string {
+	var report strings.Builder
+	dateLayout := "2006-01-02"
+
+	report.WriteString(fmt.Sprintf("# Weekly Review: %s to %s\n", review.From.Format(dateLayout), review.Until.AddDate(0, 0, -1).Format(dateLayout)))
+	report.WriteString(fmt.Sprintf("Generated: %s\n\n", review.Now.Format("2006-01-02 15:04:05")))
+
+	report.WriteString("## Summary\n")
+	report.WriteString(fmt.Sprintf("- Completed: %d\n", len(review.Completed)))
+	report.WriteString(fmt.Sprintf("- Touched: %d\n", len(review.Touched)))
+	report.WriteString(fmt.Sprintf("- Effort Delivered: %d\n", review.EffortDelivered))
+	report.WriteString(fmt.Sprintf("- Blocked: %d\n", len(review.Blocked)))
+	report.WriteString(fmt.Sprintf("- Stale (no activity for %d days): %d\n", review.StaleDays, len(review.Stale)))
+	report.WriteString("\n")
+
+	report.WriteString("## Completed\n")
+	for _, item := range review.Completed {
+		report.WriteString(fmt.Sprintf("%s _(%s)_\n", item.Line, item.Time.Format(dateLayout)))
+	}
+	writeEmpty(&report, len(review.Completed))
+
+	report.WriteString("## Touched\n")
+	for _, item := range review.Touched {
+		report.WriteString(fmt.Sprintf("%s _(%dx, last %s)_\n", item.Line, item.Count, item.Time.Format(dateLayout)))
+	}
+	writeEmpty(&report, len(review.Touched))
+
+	report.WriteString("## Blocked\n")
+	for _, item := range review.Blocked {
+		if item.Time.IsZero() {
+			report.WriteString(fmt.Sprintf("%s _(blocked, not yet journaled)_\n", item.Line))
+			continue
+		}
+		report.WriteString(fmt.Sprintf("%s _(blocked %s, since %s)_\n", item.Line, formatDays(review.Now.Sub(item.Time)), item.Time.Format(dateLayout)))
+	}
+	writeEmpty(&report, len(review.Blocked))
+
+	report.WriteString("## Stale\n")
+	for _, item := range review.Stale {
+		if item.Time.IsZero() {
+			report.WriteString(fmt.Sprintf("%s _(never touched)_\n", item.Line))
+			continue
+		}
+		report.WriteString(fmt.Sprintf("%s _(last touched %s ago)_\n", item.Line, formatDays(review.Now.Sub(item.Time))))
+	}
+	writeEmpty(&report, len(review.Stale))
+
+	return report.String()
+}
+
+// writeEmpty writes a placeholder for an empty section and ends the section
+func writeEmpty(report *strings.Builder, count int) {
+	if count == 0 {
+		report.WriteString("- None\n")
+	}
+	report.WriteString("\n")
+}
+
+// formatDays formats a duration in whole days
+func formatDays(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+// inPeriod reports whether t falls in the review period
+func inPeriod(t time.Time, opts ReviewOptions) bool {
+	if !opts.From.IsZero() && t.Before(opts.From) {
+		return false
+	}
+	return opts.Until.IsZero() || t.Before(opts.Until)
+}
+
+// isIndented reports whether a line is a subtask or detail line
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
+
+// sortedNewestFirst returns a copy of entries ordered newest first.
+// Entries with the same timestamp keep their file order.
+func sortedNewestFirst(entries []journal.Entry) []journal.Entry {
+	sorted := append([]journal.Entry{}, entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.After(sorted[j].Timestamp)
+	})
+	return sorted
+}
@@ -0,0 +1,119 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+)
+
+func TestWeekOptions(t *testing.T) {
+	now := time.Date(2024, 3, 8, 15, 0, 0, 0, time.UTC)
+	opts := WeekOptions(now, journal.DefaultTimestampFormat())
+
+	if want := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC); !opts.From.Equal(want) {
+		t.Errorf("From = %v, want %v", opts.From, want)
+	}
+	if want := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC); !opts.Until.Equal(want) {
+		t.Errorf("Until = %v, want %v", opts.Until, want)
+	}
+}
+
+func TestBuildReview(t *testing.T) {
+	now := time.Date(2024, 3, 8, 15, 0, 0, 0, time.UTC)
+	opts := WeekOptions(now, journal.DefaultTimestampFormat())
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 10, 0, 0, 0, time.UTC) }
+
+	archive := []journal.Entry{
+		{Timestamp: day(4), Line: "- [x] Ship release A3"},
+		{Timestamp: day(4), Line: "- [x] Tag release A3", Details: []string{"  - notes"}},
+		{Timestamp: day(5), Line: "- [x] Fix bug B2"},
+		{Timestamp: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Line: "- [x] Old work C5"},
+	}
+	journalEntries := []journal.Entry{
+		{Timestamp: day(7), Line: "- [W] Write docs"},
+		{Timestamp: day(6), Line: "- [W] !! Write docs"},
+		{Timestamp: day(7), Line: "- [B] Deploy"},
+		{Timestamp: day(5), Line: "- [B] Deploy"},
+		{Timestamp: day(3), Line: "- [W] Deploy"},
+		{Timestamp: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), Line: "- [W] Forgotten"},
+	}
+	todo := []string{
+		"# Work",
+		"- [W] Write docs",
+		"- [B] Deploy",
+		"- [ ] Forgotten",
+		"- [ ] Never journaled",
+		"- [ ] !! Urgent",
+		"- [x] Done but not recorded",
+	}
+
+	review := BuildReview(todo, journalEntries, archive, opts, now)
+
+	if len(review.Completed) != 3 {
+		t.Errorf("Completed = %d, want 3", len(review.Completed))
+	}
+	if review.EffortDelivered != 8 {
+		t.Errorf("EffortDelivered = %d, want 8", review.EffortDelivered)
+	}
+	if len(review.Touched) != 2 || review.Touched[0].Count != 2 {
+		t.Errorf("Touched = %+v, want Write docs (2x) and Deploy", review.Touched)
+	}
+	if len(review.Blocked) != 1 || !review.Blocked[0].Time.Equal(day(5)) {
+		t.Errorf("Blocked = %+v, want Deploy blocked since March 5", review.Blocked)
+	}
+	if len(review.Stale) != 2 || !review.Stale[0].Time.IsZero() || !strings.Contains(review.Stale[1].Line, "Forgotten") {
+		t.Errorf("Stale = %+v, want Never journaled and Forgotten", review.Stale)
+	}
+
+	report := GenerateReviewReport(review)
+	for _, want := range []string{
+		"# Weekly Review: 2024-03-02 to 2024-03-08",
+		"- Effort Delivered: 8",
+		"- [x] Fix bug B2 _(2024-03-05)_",
+		"- [W] Write docs _(2x, last 2024-03-07)_",
+		"- [B] Deploy _(blocked 3 days, since 2024-03-05)_",
+		"- [ ] Never journaled _(never touched)_",
+		"- [ ] Forgotten _(last touched 27 days ago)_",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestAnalyzeHistory(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "review-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	now := time.Now().UTC()
+	stamp := journal.DefaultTimestampFormat().Format(now)
+	files := map[string]string{
+		"todo.md":          "- [ ] Open\n",
+		"todo.xjournal.md": stamp + " - [W] Open\n",
+		"todo.xarchive.md": stamp + " - [x] Finished A2\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	review, err := AnalyzeHistory(filepath.Join(tmpDir, "todo.md"), WeekOptions(now, journal.DefaultTimestampFormat()), now)
+	if err != nil {
+		t.Fatalf("AnalyzeHistory() error = %v", err)
+	}
+	if len(review.Completed) != 1 || len(review.Touched) != 1 || len(review.Stale) != 0 {
+		t.Errorf("review = %+v, want 1 completed, 1 touched and no stale tasks", review)
+	}
+
+	if _, err := AnalyzeHistory(filepath.Join(tmpDir, "missing.md"), ReviewOptions{}, now); err == nil {
+		t.Error("Expected error for missing todo file")
+	}
+}
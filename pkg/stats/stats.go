@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
 	"github.com/robertarles/taskmasterra/v2/pkg/task"
 	"github.com/robertarles/taskmasterra/v2/pkg/utils"
 )
@@ -20,6 +21,8 @@ type TaskStats struct {
 	PriorityStats  map[string]int
 	EffortStats    map[int]int
 	Date           time.Time
+	Tasks          []task.Task     // every task of the file, for the HTML task table
+	Archive        []journal.Entry // archived tasks for the HTML report, set by the caller
 }
 
 // NewTaskStats creates a new TaskStats instance
@@ -40,6 +43,7 @@ func AnalyzeFile(filePath string) (*TaskStats, error) {
 
 	stats := NewTaskStats()
 	lines := strings.Split(content, "\n")
+	stats.Tasks = task.Parse(lines)
 
 	for _, line := range lines {
 		if !task.IsTask(line) {
@@ -53,13 +57,10 @@ func AnalyzeFile(filePath string) (*TaskStats, error) {
 			stats.CompletedTasks++
 		} else if task.IsActive(line) {
 			stats.ActiveTasks++
-		} else {
-			// Check for specific status markers
-			if strings.Contains(line, "[b]") || strings.Contains(line, "[B]") {
-				stats.BlockedTasks++
-			} else if strings.Contains(line, "[w]") || strings.Contains(line, "[W]") {
-				stats.WorkedTasks++
-			}
+		} else if task.IsBlocked(line) {
+			stats.BlockedTasks++
+		} else if task.IsWorked(line) {
+			stats.WorkedTasks++
 		}
 
 		// Count by priority
@@ -127,6 +127,32 @@ func TestAnalyzeFile(t *testing.T) {
 	}
 }
 
+func TestAnalyzeFileCountsStatusOnly(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "stats-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Markers in the title are not a status
+	testContent := `- [ ] ask why [b] tasks pile up
+- [ ] explain the [W] marker
+- [b] really blocked
+`
+	filePath := filepath.Join(tmpDir, "test.md")
+	if err := os.WriteFile(filePath, []byte(testContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	stats, err := AnalyzeFile(filePath)
+	if err != nil {
+		t.Fatalf("AnalyzeFile failed: %v", err)
+	}
+	if stats.BlockedTasks != 1 || stats.WorkedTasks != 0 {
+		t.Errorf("BlockedTasks = %d, WorkedTasks = %d, want 1 and 0", stats.BlockedTasks, stats.WorkedTasks)
+	}
+}
+
 func TestGenerateReport(t *testing.T) {
 	stats := NewTaskStats()
 	stats.TotalTasks = 10
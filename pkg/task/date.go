@@ -0,0 +1,86 @@
+package task
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// orgTimestampRegex matches an active org-mode timestamp such as
+// <2021-12-10 Fri>, <2021-12-10 Fri 09:30> or <2021-12-10 Fri .+30d>
+var orgTimestampRegex = regexp.MustCompile(`<(\d{4}-\d{2}-\d{2})(?: [^\d\s>+.-][^\s>]*)?(?: (\d{1,2}:\d{2})(?:-\d{1,2}:\d{2})?)?(?: (\.\+|\+\+|\+)(\d+)([hdwmy]))?(?: -\d+[hdwmy])?>`)
+
+// Repeater is an org-mode repeater such as "+1w" or ".+30d"
+type Repeater struct {
+	Kind     string // "+" (cumulate), "++" (catch up) or ".+" (from completion)
+	Interval int
+	Unit     byte // 'h', 'd', 'w', 'm' or 'y'
+}
+
+// String returns the repeater in org-mode syntax
+func (r Repeater) String() string {
+	return fmt.Sprintf("%s%d%c", r.Kind, r.Interval, r.Unit)
+}
+
+// RRule returns an iCalendar recurrence rule for the repeater.
+// iCalendar cannot express repeating from the completion date, so ".+" and
+// "++" repeaters recur on the fixed schedule like "+".
+func (r Repeater) RRule() string {
+	freq := map[byte]string{'h': "HOURLY", 'd': "DAILY", 'w': "WEEKLY", 'm': "MONTHLY", 'y': "YEARLY"}[r.Unit]
+	return fmt.Sprintf("FREQ=%s;INTERVAL=%d", freq, r.Interval)
+}
+
+// Date is a date attached to a task with an org-mode timestamp
+type Date struct {
+	Time     time.Time
+	HasTime  bool // false for whole-day dates
+	Repeater *Repeater
+}
+
+// String returns the date as an org-mode timestamp
+func (d Date) String() string {
+	stamp := d.Time.Format("2006-01-02 Mon")
+	if d.HasTime {
+		stamp += d.Time.Format(" 15:04")
+	}
+	if d.Repeater != nil {
+		stamp += " " + d.Repeater.String()
+	}
+	return "<" + stamp + ">"
+}
+
+// ParseDate returns the first org-mode timestamp in a line, interpreted in loc
+func ParseDate(line string, loc *time.Location) (*Date, bool) {
+	matches := orgTimestampRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, false
+	}
+
+	layout, value := "2006-01-02", matches[1]
+	if matches[2] != "" {
+		layout, value = "2006-01-02 15:04", matches[1]+" "+matches[2]
+	}
+	parsed, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		return nil, false
+	}
+
+	date := &Date{Time: parsed, HasTime: matches[2] != ""}
+	if matches[3] != "" {
+		interval, err := strconv.Atoi(matches[4])
+		if err != nil || interval < 1 {
+			return nil, false
+		}
+		date.Repeater = &Repeater{Kind: matches[3], Interval: interval, Unit: matches[5][0]}
+	}
+	return date, true
+}
+
+// StripDate removes org-mode timestamps from a title
+func StripDate(title string) string {
+	stripped := orgTimestampRegex.ReplaceAllString(title, "")
+	stripped = strings.NewReplacer("SCHEDULED:", "", "DEADLINE:", "").Replace(stripped)
+	return strings.Join(strings.Fields(stripped), " ")
+}
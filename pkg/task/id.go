@@ -0,0 +1,43 @@
+package task
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// Precompiled regex patterns for better performance
+var (
+	idStripRegex      = regexp.MustCompile(`^\s*- \[[^\]]*\]\s*(!!\s+)?`)
+	idCommentRegex    = regexp.MustCompile(`\s*<!--.*?-->`)
+	idWhitespaceRegex = regexp.MustCompile(`\s+`)
+)
+
+// IDLength is the number of hex characters in a task ID
+const IDLength = 8
+
+// ID returns a short stable identifier for a task line.
+// The ID is derived from the task title only, so it does not change when the
+// status, the active marker or the priority/effort code of the task changes.
+func ID(line string) string {
+	return hashTitle(NormalizeTitle(line))
+}
+
+// NormalizeTitle reduces a task line to its lowercase title without status,
+// active marker, priority/effort code, comments or repeated whitespace.
+func NormalizeTitle(line string) string {
+	title := idStripRegex.ReplaceAllString(line, "")
+	title = idCommentRegex.ReplaceAllString(title, "")
+	if loc := priorityEffortRegex.FindStringIndex(title); loc != nil {
+		title = title[:loc[0]] + title[loc[1]:]
+	}
+	title = idWhitespaceRegex.ReplaceAllString(title, " ")
+	return strings.ToLower(strings.TrimSpace(title))
+}
+
+// hashTitle returns the first IDLength hex characters of the SHA-1 of a title
+func hashTitle(title string) string {
+	sum := sha1.Sum([]byte(title))
+	return hex.EncodeToString(sum[:])[:IDLength]
+}
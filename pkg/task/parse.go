@@ -0,0 +1,85 @@
+package task
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Precompiled regex patterns for better performance
+var (
+	parseTitleRegex  = regexp.MustCompile(`^\s*- \[[^\]]*\]\s*(!!\s+)?`)
+	parseDetailRegex = regexp.MustCompile(`^[ \t]+(?:- )?`)
+)
+
+// Parse returns the tasks and subtasks in the lines of a todo file.
+// Indented lines that follow a task are its details; dates are read from the
+// task line first and then from its details, interpreted in local time.
+func Parse(lines []string) []Task {
+	var tasks []Task
+	section := ""
+	parentID := ""
+	current := -1
+
+	for i, line := range lines {
+		switch {
+		case SectionName(line) != "":
+			section = SectionName(line)
+			current = -1
+		case IsTask(line) || IsSubTask(line):
+			t := parseTask(line, i+1, section)
+			if t.Subtask {
+				t.ParentID = parentID
+			} else {
+				parentID = t.ID
+			}
+			tasks = append(tasks, t)
+			current = len(tasks) - 1
+		case current >= 0 && strings.TrimSpace(line) != "" && (line[0] == ' ' || line[0] == '\t'):
+			detail := parseDetailRegex.ReplaceAllString(line, "")
+			tasks[current].Details = append(tasks[current].Details, detail)
+			if tasks[current].Date == nil {
+				if date, ok := ParseDate(detail, time.Local); ok {
+					tasks[current].Date = date
+				}
+			}
+		case strings.TrimSpace(line) != "":
+			current = -1
+			parentID = ""
+		}
+	}
+
+	return tasks
+}
+
+// parseTask parses a single task or subtask line
+func parseTask(line string, lineNumber int, section string) Task {
+	info := ParseTaskInfo(strings.TrimLeft(line, " \t"))
+	t := Task{
+		Line:       line,
+		LineNumber: lineNumber,
+		ID:         ID(line),
+		Section:    section,
+		Status:     info.Status,
+		Active:     IsActive(strings.TrimLeft(line, " \t")),
+		Subtask:    IsSubTask(line),
+		Priority:   info.Priority,
+		Effort:     info.Effort,
+		Title:      CleanTitle(line),
+		Tags:       ParseTags(line),
+	}
+	if date, ok := ParseDate(line, time.Local); ok {
+		t.Date = date
+	}
+	return t
+}
+
+// CleanTitle returns the title of a task line without its status, active
+// marker, priority/effort code or dates, keeping its case and tags.
+func CleanTitle(line string) string {
+	title := parseTitleRegex.ReplaceAllString(line, "")
+	if loc := priorityEffortRegex.FindStringIndex(title); loc != nil {
+		title = title[:loc[0]] + title[loc[1]:]
+	}
+	return StripDate(title)
+}
@@ -0,0 +1,96 @@
+package task
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		wantOK   bool
+		want     time.Time
+		hasTime  bool
+		repeater string
+		rrule    string
+	}{
+		{"No date", "- [ ] A1 plain task", false, time.Time{}, false, "", ""},
+		{"Whole day", "- [ ] renew passport <2024-03-05 Tue>", true, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), false, "", ""},
+		{"With time", "- [ ] call <2024-03-05 Tue 09:30>", true, time.Date(2024, 3, 5, 9, 30, 0, 0, time.UTC), true, "", ""},
+		{"From completion", "- [ ] water plants <2021-12-10 Fri .+30d>", true, time.Date(2021, 12, 10, 0, 0, 0, 0, time.UTC), false, ".+30d", "FREQ=DAILY;INTERVAL=30"},
+		{"Weekly with time", "  SCHEDULED: <2024-03-04 Mon 08:00 +1w>", true, time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC), true, "+1w", "FREQ=WEEKLY;INTERVAL=1"},
+		{"Catch up monthly", "- [ ] rent <2024-03-01 ++1m>", true, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), false, "++1m", "FREQ=MONTHLY;INTERVAL=1"},
+		{"Invalid date", "- [ ] <2024-13-40 Fri>", false, time.Time{}, false, "", ""},
+		{"Inactive timestamp", "- [ ] [2024-03-05 Tue]", false, time.Time{}, false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, ok := ParseDate(tt.line, time.UTC)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseDate(%q) ok = %v, want %v", tt.line, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if !date.Time.Equal(tt.want) || date.HasTime != tt.hasTime {
+				t.Errorf("ParseDate(%q) = %v (time %v), want %v (time %v)", tt.line, date.Time, date.HasTime, tt.want, tt.hasTime)
+			}
+			switch {
+			case tt.repeater == "" && date.Repeater != nil:
+				t.Errorf("ParseDate(%q) repeater = %v, want none", tt.line, date.Repeater)
+			case tt.repeater != "" && (date.Repeater == nil || date.Repeater.String() != tt.repeater || date.Repeater.RRule() != tt.rrule):
+				t.Errorf("ParseDate(%q) repeater = %v, want %s (%s)", tt.line, date.Repeater, tt.repeater, tt.rrule)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	lines := []string{
+		"# Work",
+		"- [ ] !! A1 #ops rotate keys <2024-03-05 Tue 09:00 +1m>",
+		"  - rotate staging first",
+		"  - [w] C2 update vault",
+		"- [x] B3 done thing",
+		"",
+		"## Home",
+		"- [ ] water plants",
+		"  - SCHEDULED: <2021-12-10 Fri .+30d>",
+		"notes that are not tasks",
+	}
+
+	tasks := Parse(lines)
+	if len(tasks) != 4 {
+		t.Fatalf("Parse() returned %d tasks, want 4: %+v", len(tasks), tasks)
+	}
+
+	first := tasks[0]
+	if first.LineNumber != 2 || first.Section != "Work" || !first.Active || first.Priority != PriorityCritical || first.Effort != 1 {
+		t.Errorf("first task = %+v", first)
+	}
+	if first.Title != "#ops rotate keys" || strings.Join(first.Tags, ",") != "ops" {
+		t.Errorf("first task title = %q, tags = %v", first.Title, first.Tags)
+	}
+	if first.Date == nil || !first.Date.HasTime || first.Date.Repeater == nil || first.Date.Repeater.String() != "+1m" {
+		t.Errorf("first task date = %+v", first.Date)
+	}
+	if strings.Join(first.Details, "|") != "rotate staging first" {
+		t.Errorf("first task details = %q", first.Details)
+	}
+
+	sub := tasks[1]
+	if !sub.Subtask || sub.ParentID != first.ID || sub.Status != "w" || sub.Title != "update vault" {
+		t.Errorf("subtask = %+v", sub)
+	}
+
+	plants := tasks[3]
+	if plants.Section != "Home" || plants.Date == nil || plants.Date.Repeater == nil || plants.Date.Repeater.Kind != ".+" {
+		t.Errorf("scheduled task = %+v", plants)
+	}
+	if plants.ID != ID("- [x] water plants") {
+		t.Errorf("task ID %q does not match ID() of the line", plants.ID)
+	}
+}
@@ -12,6 +12,7 @@ var (
 	priorityEffortRegex = regexp.MustCompile(`\b([A-Z])(\d+)\b`)
 	statusRegex         = regexp.MustCompile(`^\s*- \[([^\]]+)\]`)
 	titleRegex          = regexp.MustCompile(`^\s*- \[[^\]]+\]\s*(.*)`)
+	tagRegex            = regexp.MustCompile(`(?:^|[\s:(])#([\w][\w\-/]*)`)
 )
 
 // Priority represents task priority levels
@@ -25,6 +26,14 @@ const (
 	PriorityCritical
 )
 
+// priorityLetters are the letters of the priorities in A1-style codes
+var priorityLetters = map[Priority]string{
+	PriorityCritical: "A",
+	PriorityHigh:     "B",
+	PriorityMedium:   "C",
+	PriorityLow:      "D",
+}
+
 // String returns the string representation of priority
 func (p Priority) String() string {
 	switch p {
@@ -43,6 +52,51 @@ func (p Priority) String() string {
 	}
 }
 
+// Letter returns the letter of the priority in A1-style codes, or "" for none
+func (p Priority) Letter() string {
+	return priorityLetters[p]
+}
+
+// ICalendar returns the priority on the iCalendar scale, 1 (highest) to 9 or
+// 0 for none. Reminder apps show only high (1), medium (5) and low (9), so
+// critical and high tasks both map to 1.
+func (p Priority) ICalendar() int {
+	switch p {
+	case PriorityCritical, PriorityHigh:
+		return 1
+	case PriorityMedium:
+		return 5
+	case PriorityLow:
+		return 9
+	default:
+		return 0
+	}
+}
+
+// Code returns the A1-style code for the priority and an effort, or "" for
+// no priority. The code needs both parts, so an effort below 1 is written as 1.
+func (p Priority) Code(effort int) string {
+	letter := p.Letter()
+	if letter == "" {
+		return ""
+	}
+	if effort < 1 {
+		effort = 1
+	}
+	return fmt.Sprintf("%s%d", letter, effort)
+}
+
+// PriorityForLetter returns the priority of a letter of an A1-style code,
+// ignoring case; letters after D have no priority
+func PriorityForLetter(letter string) Priority {
+	for priority, l := range priorityLetters {
+		if strings.EqualFold(l, letter) {
+			return priority
+		}
+	}
+	return PriorityNone
+}
+
 // ParsePriority extracts priority from task line
 func ParsePriority(line string) Priority {
 	// Look for priority markers like A1, B2, C3, etc.
@@ -51,20 +105,43 @@ func ParsePriority(line string) Priority {
 		return PriorityNone
 	}
 
-	priority := matches[1]
+	return PriorityForLetter(matches[1])
+}
 
-	switch priority {
-	case "A":
-		return PriorityCritical
-	case "B":
-		return PriorityHigh
-	case "C":
-		return PriorityMedium
-	case "D":
-		return PriorityLow
+// ParsePriorityName converts a priority letter (A-D) or name (e.g. "High") to a Priority
+func ParsePriorityName(name string) (Priority, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "a", "critical":
+		return PriorityCritical, true
+	case "b", "high":
+		return PriorityHigh, true
+	case "c", "medium":
+		return PriorityMedium, true
+	case "d", "low":
+		return PriorityLow, true
+	case "none":
+		return PriorityNone, true
 	default:
-		return PriorityNone
+		return PriorityNone, false
+	}
+}
+
+// ParseTags extracts #tags from a task line, without the leading '#'
+func ParseTags(line string) []string {
+	var tags []string
+	for _, match := range tagRegex.FindAllStringSubmatch(line, -1) {
+		tags = append(tags, match[1])
 	}
+	return tags
+}
+
+// ReplaceTags returns s with each #tag, as found by ParseTags, replaced by
+// replace(tag), where tag has no leading '#'
+func ReplaceTags(s string, replace func(tag string) string) string {
+	return tagRegex.ReplaceAllStringFunc(s, func(match string) string {
+		index := strings.Index(match, "#")
+		return match[:index] + replace(match[index+1:])
+	})
 }
 
 // ParseEffort extracts effort estimation from task line
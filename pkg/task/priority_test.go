@@ -1,6 +1,7 @@
 package task
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -320,4 +321,114 @@ func TestFormatTaskInfo(t *testing.T) {
 			}
 		})
 	}
-} 
\ No newline at end of file
+} 
+func TestParsePriorityName(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   Priority
+		wantOK bool
+	}{
+		{"A", PriorityCritical, true},
+		{"b", PriorityHigh, true},
+		{"Medium", PriorityMedium, true},
+		{" low ", PriorityLow, true},
+		{"none", PriorityNone, true},
+		{"E", PriorityNone, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := ParsePriorityName(tt.input)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ParsePriorityName(%q) = %v, %v, want %v, %v", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{"No tags", "- [ ] A1 plain task", nil},
+		{"Single tag", "- [ ] #taskmasterra add tests", []string{"taskmasterra"}},
+		{"Multiple tags", "- [X] #ferris #security only essential ports", []string{"ferris", "security"}},
+		{"Org style tags", "- [w] A1 backups :#selfHosting:#backup:", []string{"selfHosting", "backup"}},
+		{"Header is not a tag", "# Heading", nil},
+		{"Anchor inside word", "- [ ] see page#section", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseTags(tt.line)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ParseTags(%q) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriorityLetterAndCode(t *testing.T) {
+	tests := []struct {
+		priority Priority
+		effort   int
+		letter   string
+		code     string
+	}{
+		{PriorityCritical, 2, "A", "A2"},
+		{PriorityHigh, 13, "B", "B13"},
+		{PriorityMedium, 0, "C", "C1"},
+		{PriorityLow, 1, "D", "D1"},
+		{PriorityNone, 3, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.priority.String(), func(t *testing.T) {
+			if got := tt.priority.Letter(); got != tt.letter {
+				t.Errorf("Letter() = %q, want %q", got, tt.letter)
+			}
+			if got := tt.priority.Code(tt.effort); got != tt.code {
+				t.Errorf("Code(%d) = %q, want %q", tt.effort, got, tt.code)
+			}
+			if tt.letter != "" && PriorityForLetter(strings.ToLower(tt.letter)) != tt.priority {
+				t.Errorf("PriorityForLetter(%q) = %v, want %v", tt.letter, PriorityForLetter(tt.letter), tt.priority)
+			}
+		})
+	}
+	if got := PriorityForLetter("E"); got != PriorityNone {
+		t.Errorf("PriorityForLetter(E) = %v, want None", got)
+	}
+}
+
+func TestReplaceTags(t *testing.T) {
+	line := "#ferris patch (#nas) page#section :#backup:"
+	if got := ReplaceTags(line, func(tag string) string { return "@" + tag }); got != "@ferris patch (@nas) page#section :@backup:" {
+		t.Errorf("ReplaceTags() = %q", got)
+	}
+	if got := ReplaceTags(line, func(string) string { return "" }); got != " patch () page#section ::" {
+		t.Errorf("ReplaceTags() removing tags = %q", got)
+	}
+}
+
+func TestPriority_ICalendar(t *testing.T) {
+	tests := []struct {
+		priority Priority
+		expected int
+	}{
+		{PriorityCritical, 1},
+		{PriorityHigh, 1},
+		{PriorityMedium, 5},
+		{PriorityLow, 9},
+		{PriorityNone, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.priority.String(), func(t *testing.T) {
+			if got := tt.priority.ICalendar(); got != tt.expected {
+				t.Errorf("Priority.ICalendar() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+	"github.com/robertarles/taskmasterra/v2/pkg/utils"
+)
+
+// FindArchived returns the archive entries matching a task ID or a
+// case-insensitive title pattern. An exact ID match takes precedence.
+func FindArchived(entries []journal.Entry, query string) []journal.Entry {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	var byID, byPattern []journal.Entry
+	pattern := strings.ToLower(query)
+	for _, entry := range entries {
+		if ID(entry.Line) == strings.ToLower(query) {
+			byID = append(byID, entry)
+		} else if strings.Contains(strings.ToLower(entry.Line), pattern) {
+			byPattern = append(byPattern, entry)
+		}
+	}
+
+	if len(byID) > 0 {
+		return byID
+	}
+	return byPattern
+}
+
+// ReopenLine resets the status of a task line to open ("[ ]")
+func ReopenLine(line string) string {
+	loc := statusRegex.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return line
+	}
+	return line[:loc[2]] + " " + line[loc[3]:]
+}
+
+// RestoreTask moves an archived task back into its todo file.
+// The task is found by ID or title pattern, its timestamps are stripped, it is
+// reopened and inserted at the end of the section it was archived from. The
+// entry is removed from the archive first and put back if the todo file
+// cannot be written, so the task never ends up in both. It returns the
+// restored entry.
+func RestoreTask(filePath string, query string, opts ProcessOptions) (*journal.Entry, error) {
+	jm := journal.NewManager(filePath)
+	entries, err := jm.ReadArchive(opts.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive for '%s': %w", filePath, err)
+	}
+
+	matches := FindArchived(entries, query)
+	switch {
+	case len(matches) == 0:
+		return nil, fmt.Errorf("no archived task matches '%s'", query)
+	case len(matches) > 1:
+		var candidates strings.Builder
+		for _, match := range matches {
+			candidates.WriteString(fmt.Sprintf("\n  %s  %s", ID(match.Line), strings.TrimSpace(match.Line)))
+		}
+		return nil, fmt.Errorf("'%s' matches %d archived tasks, restore one by ID:%s", query, len(matches), candidates.String())
+	}
+	entry := matches[0]
+
+	content, err := utils.ReadFileContent(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file '%s': %w", filePath, err)
+	}
+
+	restored := append([]string{ReopenLine(entry.Line)}, entry.Details...)
+	lines := InsertIntoSection(strings.Split(content, "\n"), entry.Section, restored)
+
+	removed, err := jm.RemoveArchiveEntry(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove task from archive: %w", err)
+	}
+
+	if err := utils.WriteFileContent(filePath, strings.Join(lines, "\n")); err != nil {
+		if undoErr := jm.InsertArchiveLines(entry.LineNumber, removed); undoErr != nil {
+			return nil, fmt.Errorf("failed to update original file '%s': %w (the task was removed from the archive and could not be put back: %v)", filePath, err, undoErr)
+		}
+		return nil, fmt.Errorf("failed to update original file '%s': %w", filePath, err)
+	}
+
+	return &entry, nil
+}
+
+// InsertIntoSection inserts task lines after the last non-blank line of the
+// named section. Tasks without a known section are appended to the file, under
+// a new header if the section no longer exists.
+func InsertIntoSection(lines []string, section string, taskLines []string) []string {
+	start := -1
+	if section != "" {
+		for i, line := range lines {
+			if SectionName(line) == section {
+				start = i
+				break
+			}
+		}
+	}
+
+	if start < 0 {
+		// Trim trailing blank lines so the appended task follows the last content
+		end := len(lines)
+		for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+			end--
+		}
+		result := append([]string{}, lines[:end]...)
+		if section != "" {
+			if end > 0 {
+				result = append(result, "")
+			}
+			result = append(result, "## "+section, "")
+		}
+		result = append(result, taskLines...)
+		return append(result, "")
+	}
+
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if SectionName(lines[i]) != "" {
+			end = i
+			break
+		}
+	}
+
+	insertAt := start + 1
+	for i := end - 1; i > start; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			insertAt = i + 1
+			break
+		}
+	}
+
+	result := append([]string{}, lines[:insertAt]...)
+	if insertAt == start+1 {
+		// Empty section: keep a blank line between the header and the task
+		result = append(result, "")
+	}
+	result = append(result, taskLines...)
+	return append(result, lines[insertAt:]...)
+}
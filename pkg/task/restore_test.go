@@ -0,0 +1,196 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/robertarles/taskmasterra/v2/pkg/journal"
+)
+
+func TestID(t *testing.T) {
+	base := ID("- [ ] A1 Write project proposal")
+
+	same := []string{
+		"- [x] A1 Write project proposal",
+		"- [W] !! B3 Write project proposal",
+		"  - [ ] Write   project proposal",
+		"- [X] A1 Write Project Proposal <!-- section: Work -->",
+	}
+	for _, line := range same {
+		if got := ID(line); got != base {
+			t.Errorf("ID(%q) = %s, want %s", line, got, base)
+		}
+	}
+
+	if ID("- [ ] Write project plan") == base {
+		t.Error("Different titles should have different IDs")
+	}
+	if len(base) != IDLength {
+		t.Errorf("ID length = %d, want %d", len(base), IDLength)
+	}
+}
+
+func TestSectionName(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"## ACTIVE", "ACTIVE"},
+		{"# My Tasks  ", "My Tasks"},
+		{"- [ ] #tag task", ""},
+		{"#hashtag", ""},
+		{"plain text", ""},
+	}
+
+	for _, tt := range tests {
+		if got := SectionName(tt.line); got != tt.want {
+			t.Errorf("SectionName(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestReopenLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"- [x] Done task", "- [ ] Done task"},
+		{"- [X] !! Done task", "- [ ] !! Done task"},
+		{"  - [b] sub task", "  - [ ] sub task"},
+		{"- not a task", "- not a task"},
+	}
+
+	for _, tt := range tests {
+		if got := ReopenLine(tt.line); got != tt.want {
+			t.Errorf("ReopenLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestProcessTasksRecordsSection(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "taskmasterra-section-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	todoPath := filepath.Join(tmpDir, "todo.md")
+	todoContent := "# Tasks\n\n## Work\n\n- [X] B2 Ship release\n  - tag v1.0\n- [ ] Other task\n"
+	if err := os.WriteFile(todoPath, []byte(todoContent), 0644); err != nil {
+		t.Fatalf("Failed to write todo.md: %v", err)
+	}
+
+	if err := ProcessTasks(todoPath); err != nil {
+		t.Fatalf("ProcessTasks failed: %v", err)
+	}
+
+	entries, err := journal.NewManager(todoPath).ReadArchive(journal.DefaultTimestampFormat())
+	if err != nil {
+		t.Fatalf("ReadArchive failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 archive entry, got %d", len(entries))
+	}
+	if entries[0].Section != "Work" {
+		t.Errorf("Section = %q, want %q", entries[0].Section, "Work")
+	}
+	if entries[0].Line != "- [X] B2 Ship release" {
+		t.Errorf("Line = %q, section comment should be stripped", entries[0].Line)
+	}
+	if len(entries[0].Details) != 1 || entries[0].Details[0] != "  - tag v1.0" {
+		t.Errorf("Details of touched completed task not archived: %v", entries[0].Details)
+	}
+}
+
+func TestRestoreTask(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "taskmasterra-restore-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	todoPath := filepath.Join(tmpDir, "todo.md")
+	archivePath := filepath.Join(tmpDir, "todo.xarchive.md")
+	todoContent := "# Tasks\n\n## Work\n\n- [ ] A1 Current work\n\n## Home\n\n- [ ] C3 Paint fence\n"
+	archiveContent := `[2024-03-05 10:00:00 UTC] - [x] B2 Write proposal <!-- section: Work -->
+[2024-03-05 10:00:00 UTC]   - outline drafted
+[2024-03-04 10:00:00 UTC] - [x] D1 Water plants <!-- section: Garden -->
+[2024-03-03 10:00:00 UTC] - [x] C1 Old legacy task
+[2024-03-02 10:00:00 UTC] - [x] C1 Write report <!-- section: Work -->
+`
+
+	tests := []struct {
+		name         string
+		query        string
+		wantErr      string
+		wantTodo     string
+		wantArchive  []string
+		archiveLines int
+	}{
+		{
+			name:         "Restore into existing section",
+			query:        "proposal",
+			wantTodo:     "## Work\n\n- [ ] A1 Current work\n- [ ] B2 Write proposal\n  - outline drafted\n\n## Home",
+			archiveLines: 3,
+		},
+		{
+			name:         "Restore by ID into missing section",
+			query:        ID("- [x] D1 Water plants"),
+			wantTodo:     "- [ ] C3 Paint fence\n\n## Garden\n\n- [ ] D1 Water plants\n",
+			archiveLines: 4,
+		},
+		{
+			name:         "Restore legacy entry without section",
+			query:        "legacy",
+			wantTodo:     "- [ ] C3 Paint fence\n- [ ] C1 Old legacy task\n",
+			archiveLines: 4,
+		},
+		{
+			name:    "Ambiguous pattern",
+			query:   "write",
+			wantErr: "matches 2 archived tasks",
+		},
+		{
+			name:    "No match",
+			query:   "nothing like this",
+			wantErr: "no archived task",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(todoPath, []byte(todoContent), 0644); err != nil {
+				t.Fatalf("Failed to write todo.md: %v", err)
+			}
+			if err := os.WriteFile(archivePath, []byte(archiveContent), 0644); err != nil {
+				t.Fatalf("Failed to write archive: %v", err)
+			}
+
+			_, err := RestoreTask(todoPath, tt.query, DefaultProcessOptions())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("RestoreTask() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RestoreTask() failed: %v", err)
+			}
+
+			todo, _ := os.ReadFile(todoPath)
+			if !strings.Contains(string(todo), tt.wantTodo) {
+				t.Errorf("Todo file does not contain %q:\n%s", tt.wantTodo, todo)
+			}
+
+			archive, _ := os.ReadFile(archivePath)
+			if strings.Contains(string(archive), tt.query) {
+				t.Errorf("Restored task still in archive:\n%s", archive)
+			}
+			if got := len(strings.Split(strings.TrimSpace(string(archive)), "\n")); got != tt.archiveLines {
+				t.Errorf("Archive has %d lines, want %d:\n%s", got, tt.archiveLines, archive)
+			}
+		})
+	}
+}
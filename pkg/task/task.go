@@ -16,15 +16,31 @@ var (
 	completedTaskRegex = regexp.MustCompile(`^\s*- \[[Xx]\]`)
 	activeTaskRegex    = regexp.MustCompile(`^\s*- \[.\] !! `)
 	touchedTaskRegex   = regexp.MustCompile(`(^- \[[BWX]\]|^\s+- \[[BWX]\])`)
+	blockedTaskRegex   = regexp.MustCompile(`^\s*- \[[Bb]\]`)
+	workedTaskRegex    = regexp.MustCompile(`^\s*- \[[Ww]\]`)
 	taskRegex          = regexp.MustCompile(`^- \[`)
 	subTaskRegex       = regexp.MustCompile(`^[ \t]+- \[`)
 	taskDetailRegex    = regexp.MustCompile(`^[ \t]+- `)
+	headerRegex        = regexp.MustCompile(`^#{1,6}\s+(.+)$`)
 )
 
 // Task represents a task item with its status and details.
-// This is a simple wrapper around the raw line content.
+// Line holds the raw line content; the other fields are filled in by Parse.
 type Task struct {
-	Line string
+	Line       string
+	LineNumber int // 1-based line number in the todo file
+	ID         string
+	Section    string
+	Status     string
+	Active     bool
+	Subtask    bool
+	ParentID   string // ID of the enclosing task for subtasks
+	Priority   Priority
+	Effort     int
+	Title      string // without status, active marker, priority/effort code or dates
+	Tags       []string
+	Date       *Date
+	Details    []string // indented detail lines without the leading "- "
 }
 
 // IsCompleted checks if a task is marked as completed.
@@ -65,6 +81,24 @@ func IsTouched(line string) bool {
 	return touchedTaskRegex.MatchString(line)
 }
 
+// IsBlocked checks if a task or subtask is blocked.
+// Returns true if the line has a [b] or [B] status.
+func IsBlocked(line string) bool {
+	if !IsTask(line) && !IsSubTask(line) {
+		return false
+	}
+	return blockedTaskRegex.MatchString(line)
+}
+
+// IsWorked checks if a task or subtask was worked on.
+// Returns true if the line has a [w] or [W] status.
+func IsWorked(line string) bool {
+	if !IsTask(line) && !IsSubTask(line) {
+		return false
+	}
+	return workedTaskRegex.MatchString(line)
+}
+
 // IsTask checks if a line represents a task.
 // Returns true if the line starts with "- [" (task list item).
 func IsTask(line string) bool {
@@ -83,6 +117,16 @@ func IsTaskDetail(line string) bool {
 	return taskDetailRegex.MatchString(line)
 }
 
+// SectionName returns the header text if the line is a markdown header.
+// Returns an empty string for any other line.
+func SectionName(line string) string {
+	matches := headerRegex.FindStringSubmatch(strings.TrimRight(line, " \t"))
+	if len(matches) < 2 {
+		return ""
+	}
+	return strings.TrimSpace(matches[1])
+}
+
 // ReplaceStatus replaces the task status marker in a line.
 // Creates a new regex pattern for the specific marker and performs the replacement.
 func ReplaceStatus(line string, oldMarker, newMarker rune) string {
@@ -103,40 +147,102 @@ func ConvertActiveToTouched(line string) string {
 	return line
 }
 
-// ProcessTasks processes a todo file, moving completed tasks to archive and touched tasks to journal.
+// CompleteByID marks the open tasks and subtasks whose ID is in ids as
+// completed ("[X]") so that the next recordkeep journals and archives them.
+// It returns the updated lines and the IDs of the tasks that were marked.
+func CompleteByID(lines []string, ids map[string]bool) ([]string, []string) {
+	updated := make([]string, len(lines))
+	var marked []string
+	for i, line := range lines {
+		updated[i] = line
+		if (!IsTask(line) && !IsSubTask(line)) || IsCompleted(line) || !ids[ID(line)] {
+			continue
+		}
+		loc := statusRegex.FindStringSubmatchIndex(line)
+		if loc == nil {
+			continue
+		}
+		updated[i] = line[:loc[2]] + "X" + line[loc[3]:]
+		marked = append(marked, ID(line))
+	}
+	return updated, marked
+}
+
+// ProcessOptions controls how ProcessTasksWithOptions stamps and writes journal and archive entries.
+type ProcessOptions struct {
+	Timestamp     journal.TimestampFormat
+	JournalLayout string // journal.LayoutPrepend or journal.LayoutAppend
+}
+
+// DefaultProcessOptions returns the options used by ProcessTasks.
+func DefaultProcessOptions() ProcessOptions {
+	return ProcessOptions{
+		Timestamp:     journal.DefaultTimestampFormat(),
+		JournalLayout: journal.LayoutPrepend,
+	}
+}
+
+// Summary lists the task lines that ProcessTasksWithOptions journaled and archived.
+// Detail lines are not included.
+type Summary struct {
+	Journaled []string
+	Archived  []string
+}
+
+// ProcessTasks processes a todo file using the default options.
+// See ProcessTasksWithOptions for details.
+func ProcessTasks(filePath string) error {
+	_, err := ProcessTasksWithOptions(filePath, DefaultProcessOptions())
+	return err
+}
+
+// ProcessTasksWithOptions processes a todo file, moving completed tasks to archive and touched tasks to journal.
 // This is the main workflow function that:
 // - Reads the todo file
 // - Processes each task line
 // - Moves completed tasks to archive with timestamps
 // - Moves touched/active tasks to journal with timestamps
 // - Updates the original file with converted status markers
-func ProcessTasks(filePath string) error {
+// It returns a summary of the tasks that were journaled and archived.
+func ProcessTasksWithOptions(filePath string, opts ProcessOptions) (*Summary, error) {
 	// Read the original file
 	content, err := utils.ReadFileContent(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to read file '%s': %w", filePath, err)
+		return nil, fmt.Errorf("failed to read file '%s': %w", filePath, err)
 	}
 
 	lines := strings.Split(content, "\n")
 	jm := journal.NewManager(filePath)
-	timestamp := journal.FormatTimestamp()
+	jm.Timestamp = opts.Timestamp
+	if opts.JournalLayout != "" {
+		jm.Layout = opts.JournalLayout
+	}
+	timestamp := opts.Timestamp.Now()
 
 	var journalEntries, archiveEntries, updatedLines []string
+	summary := &Summary{}
+	section := ""
 	
 	for i := 0; i < len(lines); {
 		line := lines[i]
 		nextLine := i + 1
 
+		if name := SectionName(line); name != "" {
+			section = name
+		}
+
 		if IsTouched(line) || IsActive(line) {
 			entry := fmt.Sprintf("%s %s", timestamp, line)
 			journalEntries = append(journalEntries, entry)
+			summary.Journaled = append(summary.Journaled, line)
 
 			if !IsCompleted(line) {
 				modifiedLine := ConvertActiveToTouched(line)
 				updatedLines = append(updatedLines, modifiedLine)
 			} else {
-				// Archive parent line with timestamp
-				archiveEntries = append(archiveEntries, fmt.Sprintf("%s %s", timestamp, line))
+				// Archive parent line with timestamp and its section
+				archiveEntries = append(archiveEntries, fmt.Sprintf("%s %s%s", timestamp, line, journal.SectionComment(section)))
+				summary.Archived = append(summary.Archived, line)
 			}
 
 			// Process child items
@@ -145,6 +251,9 @@ func ProcessTasks(filePath string) error {
 					journalEntries = append(journalEntries, lines[j])
 					if !IsCompleted(line) {
 						updatedLines = append(updatedLines, lines[j])
+					} else {
+						// Archive child detail line with timestamp so it can be restored
+						archiveEntries = append(archiveEntries, fmt.Sprintf("%s %s", timestamp, lines[j]))
 					}
 					nextLine = j + 1
 				} else {
@@ -152,8 +261,9 @@ func ProcessTasks(filePath string) error {
 				}
 			}
 		} else if IsCompleted(line) {
-			// Archive parent line with timestamp
-			archiveEntries = append(archiveEntries, fmt.Sprintf("%s %s", timestamp, line))
+			// Archive parent line with timestamp and its section
+			archiveEntries = append(archiveEntries, fmt.Sprintf("%s %s%s", timestamp, line, journal.SectionComment(section)))
+			summary.Archived = append(summary.Archived, line)
 
 			// Process child items
 			for j := nextLine; j < len(lines); j++ {
@@ -174,17 +284,17 @@ func ProcessTasks(filePath string) error {
 
 	// Write to journal and archive
 	if err := jm.WriteToJournal(journalEntries); err != nil {
-		return fmt.Errorf("failed to write journal entries for file '%s': %w", filePath, err)
+		return nil, fmt.Errorf("failed to write journal entries for file '%s': %w", filePath, err)
 	}
 
 	if err := jm.WriteToArchive(archiveEntries); err != nil {
-		return fmt.Errorf("failed to write archive entries for file '%s': %w", filePath, err)
+		return nil, fmt.Errorf("failed to write archive entries for file '%s': %w", filePath, err)
 	}
 
 	// Update original file
 	if err := utils.WriteFileContent(filePath, strings.Join(updatedLines, "\n")); err != nil {
-		return fmt.Errorf("failed to update original file '%s': %w", filePath, err)
+		return nil, fmt.Errorf("failed to update original file '%s': %w", filePath, err)
 	}
 
-	return nil
+	return summary, nil
 } 
\ No newline at end of file
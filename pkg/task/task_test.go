@@ -211,6 +211,52 @@ func TestIsTaskDetailAndIsSubTask(t *testing.T) {
 	}
 }
 
+func TestIsBlocked(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"- [b] waiting on review", true},
+		{"- [B] !! A1 blocked and active", true},
+		{"  - [b] blocked subtask", true},
+		{"- [ ] open task", false},
+		{"- [w] worked task", false},
+		{"  - detail [b]", false},
+		{"[b] not a task", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := IsBlocked(tt.line); got != tt.want {
+				t.Errorf("IsBlocked(%q) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsWorked(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"- [w] worked on today", true},
+		{"- [W] !! A1 worked and active", true},
+		{"  - [w] worked subtask", true},
+		{"- [ ] open task", false},
+		{"- [b] blocked task", false},
+		{"- [ ] ask about [w] markers", false},
+		{"[w] not a task", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := IsWorked(tt.line); got != tt.want {
+				t.Errorf("IsWorked(%q) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestActiveMarkerPosition(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -301,4 +347,39 @@ func TestActiveMarkerPosition(t *testing.T) {
 			}
 		})
 	}
-} 
\ No newline at end of file
+} 
+func TestCompleteByID(t *testing.T) {
+	lines := []string{
+		"# Work",
+		"- [ ] !! Call the bank",
+		"  - [w] Find account number",
+		"- [x] Already done",
+		"- [ ] Untouched",
+	}
+	ids := map[string]bool{
+		ID("- [ ] Call the bank"):       true,
+		ID("- [ ] Find account number"): true,
+		ID("- [ ] Already done"):        true,
+	}
+
+	updated, marked := CompleteByID(lines, ids)
+
+	want := []string{
+		"# Work",
+		"- [X] !! Call the bank",
+		"  - [X] Find account number",
+		"- [x] Already done",
+		"- [ ] Untouched",
+	}
+	for i := range want {
+		if updated[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, updated[i], want[i])
+		}
+	}
+	if len(marked) != 2 {
+		t.Errorf("marked = %v, want 2 IDs", marked)
+	}
+	if lines[1] != "- [ ] !! Call the bank" {
+		t.Error("CompleteByID modified its input")
+	}
+}
@@ -0,0 +1,209 @@
+// Package taskwarrior reads and writes the JSON task format of Taskwarrior's
+// `task export` and `task import` commands.
+package taskwarrior
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeLayout is the UTC date format used for every Taskwarrior date field
+const timeLayout = "20060102T150405Z"
+
+// Statuses
+const (
+	StatusPending   = "pending"
+	StatusCompleted = "completed"
+	StatusDeleted   = "deleted"
+	StatusWaiting   = "waiting"
+	StatusRecurring = "recurring" // the template that repeating tasks are created from
+)
+
+// Priorities
+const (
+	PriorityHigh   = "H"
+	PriorityMedium = "M"
+	PriorityLow    = "L"
+)
+
+// Time is a Taskwarrior date, written as 20060102T150405Z
+type Time struct {
+	time.Time
+}
+
+// NewTime returns a pointer to t as a Taskwarrior date, or nil for the zero time
+func NewTime(t time.Time) *Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &Time{t}
+}
+
+// MarshalJSON writes the time in UTC in the Taskwarrior date format
+func (t Time) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.UTC().Format(timeLayout))
+}
+
+// UnmarshalJSON reads the Taskwarrior date format or RFC 3339
+func (t *Time) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.Parse(timeLayout, s)
+	if err != nil {
+		parsed, err = time.Parse(time.RFC3339, s)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid Taskwarrior date '%s'", s)
+	}
+	t.Time = parsed
+	return nil
+}
+
+// Annotation is a timestamped note on a task
+type Annotation struct {
+	Entry       *Time  `json:"entry,omitempty"`
+	Description string `json:"description"`
+}
+
+// Task is a Taskwarrior task. Fields that taskmasterra does not use, such as
+// user-defined attributes other than effort, are dropped when reading.
+type Task struct {
+	UUID        string       `json:"uuid,omitempty"`
+	Description string       `json:"description"`
+	Status      string       `json:"status"`
+	Entry       *Time        `json:"entry,omitempty"`
+	Modified    *Time        `json:"modified,omitempty"`
+	Start       *Time        `json:"start,omitempty"`
+	End         *Time        `json:"end,omitempty"`
+	Due         *Time        `json:"due,omitempty"`
+	Recur       string       `json:"recur,omitempty"`
+	Priority    string       `json:"priority,omitempty"`
+	Project     string       `json:"project,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+	Urgency     float64      `json:"urgency,omitempty"`
+
+	// Effort is a user-defined attribute that keeps the taskmasterra effort.
+	// Taskwarrior keeps unknown attributes on import; `task config
+	// uda.effort.type numeric` makes it editable.
+	Effort int `json:"effort,omitempty"`
+}
+
+// Parse reads `task export` output: a JSON array, or one JSON object per
+// line as written by Taskwarrior versions before 2.6
+func Parse(data []byte) ([]Task, error) {
+	data = bytes.TrimSpace(data)
+	var tasks []Task
+	if len(data) == 0 {
+		return tasks, nil
+	}
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &tasks); err != nil {
+			return nil, fmt.Errorf("failed to parse Taskwarrior export: %w", err)
+		}
+		return tasks, nil
+	}
+
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSuffix(bytes.TrimSpace(line), []byte(","))
+		if len(line) == 0 {
+			continue
+		}
+		var t Task
+		if err := json.Unmarshal(line, &t); err != nil {
+			return nil, fmt.Errorf("failed to parse Taskwarrior export line %d: %w", i+1, err)
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// Encode returns tasks as an indented JSON array that `task import` reads
+func Encode(tasks []Task) (string, error) {
+	if tasks == nil {
+		tasks = []Task{}
+	}
+	data, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal Taskwarrior tasks: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// UUID returns a name-based (version 5 style) UUID for a name, so the same
+// task gets the same UUID in every export and `task import` updates it
+// instead of adding a copy
+func UUID(name string) string {
+	sum := sha1.Sum([]byte("taskmasterra:" + name))
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// recurRegex matches a duration such as "2weeks", "3d", "1mo" or "P2W"
+var recurRegex = regexp.MustCompile(`^(?i)P?(\d*)\s*(h|hrs?|hours?|d|days?|w|wks?|weeks?|mo|mths?|months?|y|yrs?|years?)$`)
+
+// namedRecur are the named recurrence periods Taskwarrior accepts
+var namedRecur = map[string]struct {
+	interval int
+	unit     byte
+}{
+	"hourly":    {1, 'h'},
+	"daily":     {1, 'd'},
+	"day":       {1, 'd'},
+	"weekly":    {1, 'w'},
+	"week":      {1, 'w'},
+	"biweekly":  {2, 'w'},
+	"fortnight": {2, 'w'},
+	"monthly":   {1, 'm'},
+	"month":     {1, 'm'},
+	"bimonthly": {2, 'm'},
+	"quarterly": {3, 'm'},
+	"yearly":    {1, 'y'},
+	"annual":    {1, 'y'},
+	"year":      {1, 'y'},
+	"biannual":  {2, 'y'},
+	"biyearly":  {2, 'y'},
+}
+
+// ParseRecur converts a recur value to an interval and an org repeater unit
+// ('h', 'd', 'w', 'm' or 'y'). Periods with no org equivalent, such as
+// "weekdays", return false.
+func ParseRecur(recur string) (int, byte, bool) {
+	recur = strings.ToLower(strings.TrimSpace(recur))
+	if named, ok := namedRecur[recur]; ok {
+		return named.interval, named.unit, true
+	}
+	matches := recurRegex.FindStringSubmatch(recur)
+	if matches == nil {
+		return 0, 0, false
+	}
+	interval := 1
+	if matches[1] != "" {
+		interval, _ = strconv.Atoi(matches[1])
+	}
+	unit := strings.ToLower(matches[2])
+	switch {
+	case strings.HasPrefix(unit, "mo") || strings.HasPrefix(unit, "mth"):
+		return interval, 'm', true
+	default:
+		return interval, unit[0], true
+	}
+}
+
+// FormatRecur returns the recur value for an interval and org repeater unit
+func FormatRecur(interval int, unit byte) string {
+	names := map[byte]string{'h': "hours", 'd': "days", 'w': "weeks", 'm': "months", 'y': "years"}
+	if interval == 1 {
+		return map[byte]string{'h': "hourly", 'd': "daily", 'w': "weekly", 'm': "monthly", 'y': "yearly"}[unit]
+	}
+	return fmt.Sprintf("%d%s", interval, names[unit])
+}
@@ -0,0 +1,122 @@
+package taskwarrior
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	array := `[
+{"id":1,"description":"Write report","status":"pending","entry":"20240301T120000Z","due":"20240309T060000Z","priority":"H","project":"Work","tags":["docs"],"urgency":9.1,
+ "annotations":[{"entry":"20240302T080000Z","description":"outline done"}],"uda_custom":"x"},
+{"id":0,"description":"Pay rent","status":"completed","end":"2024-03-05T10:00:00Z","effort":3}
+]`
+	lines := `{"description":"Old style","status":"pending"},
+{"description":"Second","status":"deleted"}
+`
+
+	tests := []struct {
+		name  string
+		data  string
+		count int
+	}{
+		{"array", array, 2},
+		{"one object per line", lines, 2},
+		{"empty", "  \n", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := Parse([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(tasks) != tt.count {
+				t.Fatalf("got %d tasks, want %d", len(tasks), tt.count)
+			}
+		})
+	}
+
+	tasks, _ := Parse([]byte(array))
+	first := tasks[0]
+	if first.Description != "Write report" || first.Priority != PriorityHigh || first.Project != "Work" || first.Urgency != 9.1 {
+		t.Errorf("first task = %+v", first)
+	}
+	if !first.Due.Equal(time.Date(2024, 3, 9, 6, 0, 0, 0, time.UTC)) || len(first.Annotations) != 1 || first.Annotations[0].Description != "outline done" {
+		t.Errorf("first task = %+v", first)
+	}
+	if second := tasks[1]; !second.End.Equal(time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)) || second.Effort != 3 {
+		t.Errorf("second task = %+v", second)
+	}
+
+	if _, err := Parse([]byte(`[{"description": "bad date", "due": "tomorrow"}]`)); err == nil {
+		t.Error("Parse() should reject invalid dates")
+	}
+}
+
+func TestEncode(t *testing.T) {
+	data, err := Encode([]Task{{
+		UUID:        UUID("abc"),
+		Description: "Ship",
+		Status:      StatusPending,
+		Due:         NewTime(time.Date(2024, 3, 9, 7, 30, 0, 0, time.FixedZone("MST", -7*3600))),
+		Start:       NewTime(time.Time{}),
+	}})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if !strings.Contains(data, `"due": "20240309T143000Z"`) || strings.Contains(data, "start") {
+		t.Errorf("Encode() =\n%s", data)
+	}
+
+	tasks, err := Parse([]byte(data))
+	if err != nil || len(tasks) != 1 || tasks[0].UUID != UUID("abc") {
+		t.Errorf("Parse(Encode()) = %+v, %v", tasks, err)
+	}
+
+	if empty, _ := Encode(nil); empty != "[]\n" {
+		t.Errorf("Encode(nil) = %q, want []", empty)
+	}
+}
+
+func TestUUID(t *testing.T) {
+	uuid := UUID("1a2b3c4d")
+	if uuid != UUID("1a2b3c4d") || uuid == UUID("other") {
+		t.Error("UUID() should be stable and differ between names")
+	}
+	if len(uuid) != 36 || uuid[14] != '5' || strings.Count(uuid, "-") != 4 {
+		t.Errorf("UUID() = %s, want a version 5 UUID", uuid)
+	}
+}
+
+func TestRecur(t *testing.T) {
+	tests := []struct {
+		recur    string
+		interval int
+		unit     byte
+		ok       bool
+	}{
+		{"weekly", 1, 'w', true},
+		{"quarterly", 3, 'm', true},
+		{"2weeks", 2, 'w', true},
+		{"3d", 3, 'd', true},
+		{"1mo", 1, 'm', true},
+		{"6 months", 6, 'm', true},
+		{"P2Y", 2, 'y', true},
+		{"weekdays", 0, 0, false},
+		{"", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		interval, unit, ok := ParseRecur(tt.recur)
+		if interval != tt.interval || unit != tt.unit || ok != tt.ok {
+			t.Errorf("ParseRecur(%q) = %d, %c, %v, want %d, %c, %v", tt.recur, interval, unit, ok, tt.interval, tt.unit, tt.ok)
+		}
+		if tt.ok {
+			if gotInterval, gotUnit, _ := ParseRecur(FormatRecur(tt.interval, tt.unit)); gotInterval != tt.interval || gotUnit != tt.unit {
+				t.Errorf("ParseRecur(FormatRecur(%d, %c)) = %d, %c", tt.interval, tt.unit, gotInterval, gotUnit)
+			}
+		}
+	}
+}
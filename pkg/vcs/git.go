@@ -0,0 +1,94 @@
+// Package vcs commits taskmasterra files to the git repository that contains them.
+// It shells out to the git binary so that the user's git configuration,
+// hooks and signing settings apply as they would for a manual commit.
+package vcs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Repo is a git working tree
+type Repo struct {
+	Root string
+}
+
+// FindRepo returns the git working tree containing path.
+// It returns nil without an error when path is not inside a repository or
+// git is not installed.
+func FindRepo(path string) (*Repo, error) {
+	dir := path
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		dir = filepath.Dir(path)
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, nil
+	}
+
+	output, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		// Not a repository, or a bare one without a working tree
+		return nil, nil
+	}
+
+	return &Repo{Root: strings.TrimSpace(output)}, nil
+}
+
+// Commit stages the given files and commits only those files with message.
+// Files that do not exist are skipped. It returns false without committing
+// when none of the files have changes.
+func (r *Repo) Commit(paths []string, message string) (bool, error) {
+	var existing []string
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			existing = append(existing, path)
+		}
+	}
+	if len(existing) == 0 {
+		return false, nil
+	}
+
+	if _, err := run(r.Root, append([]string{"add", "--"}, existing...)...); err != nil {
+		return false, fmt.Errorf("failed to stage files: %w", err)
+	}
+
+	// "diff --quiet" exits with 1 when there are staged changes
+	_, err := run(r.Root, append([]string{"diff", "--cached", "--quiet", "--"}, existing...)...)
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return false, nil
+	case !errors.As(err, &exitErr) || exitErr.ExitCode() != 1:
+		return false, fmt.Errorf("failed to check for staged changes: %w", err)
+	}
+
+	// Commit only these paths so that unrelated staged work is left alone
+	if _, err := run(r.Root, append([]string{"commit", "-m", message, "--"}, existing...)...); err != nil {
+		return false, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return true, nil
+}
+
+// run executes a git command in dir and returns its standard output
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,111 @@
+package vcs
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initRepo creates a temporary git repository with a local identity
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := os.MkdirTemp("", "vcs-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "commit.gpgsign", "false"},
+	} {
+		if _, err := run(dir, args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+	return dir
+}
+
+func TestFindRepo(t *testing.T) {
+	dir := initRepo(t)
+	todoPath := filepath.Join(dir, "todo.md")
+
+	repo, err := FindRepo(todoPath)
+	if err != nil {
+		t.Fatalf("FindRepo() error = %v", err)
+	}
+	if repo == nil {
+		t.Fatal("FindRepo() = nil, want repository")
+	}
+	want, _ := filepath.EvalSymlinks(dir)
+	got, _ := filepath.EvalSymlinks(repo.Root)
+	if got != want {
+		t.Errorf("Root = %s, want %s", got, want)
+	}
+
+	outside, err := os.MkdirTemp("", "vcs-outside-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(outside)
+	repo, err = FindRepo(filepath.Join(outside, "todo.md"))
+	if err != nil {
+		t.Fatalf("FindRepo() error = %v", err)
+	}
+	if repo != nil {
+		// The temp directory itself may live inside a repository on some machines
+		t.Skipf("temp directory is inside repository %s", repo.Root)
+	}
+}
+
+func TestCommit(t *testing.T) {
+	dir := initRepo(t)
+	repo := &Repo{Root: dir}
+
+	todoPath := filepath.Join(dir, "todo.md")
+	otherPath := filepath.Join(dir, "other.md")
+	for _, path := range []string{todoPath, otherPath} {
+		if err := os.WriteFile(path, []byte("- [ ] Task\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	if _, err := run(dir, "add", "other.md"); err != nil {
+		t.Fatalf("Failed to stage other.md: %v", err)
+	}
+
+	committed, err := repo.Commit([]string{todoPath, filepath.Join(dir, "missing.md")}, "recordkeep todo.md")
+	if err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if !committed {
+		t.Fatal("Commit() = false, want true")
+	}
+
+	files, err := run(dir, "show", "--name-only", "--format=%s", "HEAD")
+	if err != nil {
+		t.Fatalf("git show failed: %v", err)
+	}
+	if !strings.Contains(files, "recordkeep todo.md") || !strings.Contains(files, "todo.md") {
+		t.Errorf("HEAD = %q, want commit of todo.md", files)
+	}
+	if strings.Contains(files, "other.md") {
+		t.Errorf("HEAD = %q, other staged files should not be committed", files)
+	}
+
+	// Nothing changed since the last commit
+	committed, err = repo.Commit([]string{todoPath}, "recordkeep todo.md")
+	if err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if committed {
+		t.Error("Commit() = true for unchanged files, want false")
+	}
+}